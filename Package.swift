@@ -12,13 +12,24 @@ let package = Package(
     targets: [
         .executableTarget(
             name: "apple-contacts",
+            dependencies: [
+                "AppleContactsKit",
+            ],
+            path: "Sources/apple-contacts"
+        ),
+        .target(
+            name: "AppleContactsKit",
             dependencies: [
                 .product(name: "ArgumentParser", package: "swift-argument-parser"),
             ],
-            path: "Sources",
-            swiftSettings: [
-                .unsafeFlags(["-parse-as-library"]),
-            ]
+            path: "Sources/AppleContactsKit"
+        ),
+        .testTarget(
+            name: "AppleContactsKitTests",
+            dependencies: [
+                "AppleContactsKit",
+            ],
+            path: "Tests/AppleContactsKitTests"
         ),
     ]
 )
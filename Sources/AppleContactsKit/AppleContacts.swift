@@ -0,0 +1,38 @@
+import ArgumentParser
+import Foundation
+
+/// Root command. The `apple-contacts` executable only calls `AppleContacts.main()`;
+/// everything else lives in this library so the test target can import it.
+public struct AppleContacts: ParsableCommand {
+    public static let configuration = CommandConfiguration(
+        commandName: "apple-contacts",
+        abstract: "CLI tool to search and query Apple Contacts",
+        version: "0.3.3",
+        subcommands: [
+            Search.self,
+            Show.self,
+            Resolve.self,
+            List.self,
+            Groups.self,
+            Export.self,
+            Import.self,
+            Create.self,
+            Edit.self,
+            Delete.self,
+            VerifyVCard.self,
+            Diff.self,
+            Report.self,
+            Lint.self,
+            Merge.self,
+            FixEncoding.self,
+            Prune.self,
+            Permissions.self,
+            Bench.self,
+            SelftestRoundtrip.self,
+            InstallSkill.self,
+        ],
+        defaultSubcommand: nil
+    )
+
+    public init() {}
+}
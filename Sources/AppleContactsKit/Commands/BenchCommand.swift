@@ -0,0 +1,135 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct Bench: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "Time common queries against the contacts database",
+        discussion: """
+            Developer tool for reporting performance numbers. Runs a full
+            list, a name search and a full-detail lookup (as used by show)
+            against the real database and prints min/median/max in ms.
+
+            The search term defaults to the first listed contact's last name.
+
+            Examples:
+              apple-contacts bench
+              apple-contacts bench --iterations 20 --warmup 2
+              apple-contacts bench --term fisher
+            """,
+        shouldDisplay: false
+    )
+
+    @Option(name: .long, help: "Number of timed runs per query")
+    var iterations = 10
+
+    @Option(name: .long, help: "Untimed runs per query before measuring")
+    var warmup = 1
+
+    @Option(name: .long, help: "Name to search for")
+    var term: String?
+
+    @Flag(name: .shortAndLong, help: "Output as JSON")
+    var json = false
+
+    func validate() throws {
+        if iterations < 1 {
+            throw ValidationError("--iterations must be at least 1")
+        }
+        if warmup < 0 {
+            throw ValidationError("--warmup cannot be negative")
+        }
+    }
+
+    func run() throws {
+        let service = ContactsService()
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        let contacts = try service.listContacts()
+        guard let sample = contacts.first else {
+            throw ValidationError("No contacts to benchmark against")
+        }
+        let searchTerm = term ?? (sample.familyName.isEmpty ? sample.fullName : sample.familyName)
+
+        let results = [
+            try measure("list") { _ = try service.listContacts() },
+            try measure("search") { _ = try service.searchByName(searchTerm) },
+            try measure("show") { _ = try service.getContact(id: sample.identifier) },
+        ]
+
+        if json {
+            printJSON(results, contactCount: contacts.count, searchTerm: searchTerm)
+        } else {
+            printTable(results, contactCount: contacts.count, searchTerm: searchTerm)
+        }
+    }
+
+    private struct Timing {
+        let name: String
+        let samples: [Double]
+
+        var min: Double { samples.min() ?? 0 }
+        var max: Double { samples.max() ?? 0 }
+
+        var median: Double {
+            let sorted = samples.sorted()
+            guard !sorted.isEmpty else { return 0 }
+            let middle = sorted.count / 2
+            return sorted.count % 2 == 0 ? (sorted[middle - 1] + sorted[middle]) / 2 : sorted[middle]
+        }
+    }
+
+    /// Run a query --warmup times untimed, then --iterations times, recording milliseconds
+    private func measure(_ name: String, _ body: () throws -> Void) throws -> Timing {
+        for _ in 0..<warmup {
+            try body()
+        }
+
+        var samples: [Double] = []
+        for _ in 0..<iterations {
+            let start = DispatchTime.now().uptimeNanoseconds
+            try body()
+            samples.append(Double(DispatchTime.now().uptimeNanoseconds - start) / 1_000_000)
+        }
+        return Timing(name: name, samples: samples)
+    }
+
+    private func printTable(_ results: [Timing], contactCount: Int, searchTerm: String) {
+        print("\(contactCount) contacts, \(iterations) iteration(s), \(warmup) warmup, search term \"\(searchTerm)\"\n")
+        print("QUERY     MIN (ms)    MEDIAN (ms) MAX (ms)")
+        for timing in results {
+            let columns = [timing.min, timing.median, timing.max]
+                .map { String(format: "%.2f", $0).padding(toLength: 11, withPad: " ", startingAt: 0) }
+                .joined(separator: " ")
+            print("\(timing.name.padding(toLength: 9, withPad: " ", startingAt: 0)) \(columns)")
+        }
+    }
+
+    private func printJSON(_ results: [Timing], contactCount: Int, searchTerm: String) {
+        let data: [String: Any] = [
+            "contacts": contactCount,
+            "iterations": iterations,
+            "warmup": warmup,
+            "searchTerm": searchTerm,
+            "results": results.map { timing -> [String: Any] in
+                [
+                    "query": timing.name,
+                    "minMs": timing.min,
+                    "medianMs": timing.median,
+                    "maxMs": timing.max,
+                ]
+            },
+        ]
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: [.prettyPrinted, .sortedKeys]),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+}
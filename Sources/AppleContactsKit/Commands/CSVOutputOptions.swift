@@ -0,0 +1,69 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+/// `--format csv` for spreadsheets (`list`, `search`): one row per contact with the name,
+/// organization and primary phone and email; further phones and emails are joined with
+/// "; " in their own columns
+struct CSVOutputOptions: ParsableArguments {
+    enum Format: String, ExpressibleByArgument, CaseIterable {
+        case table
+        case csv
+    }
+
+    @Option(name: .long, help: "Output format: table (default) or csv (use --json for JSON)")
+    var format: Format = .table
+
+    @Option(name: .shortAndLong, help: "With --format csv, write to this file instead of stdout")
+    var output: String?
+
+    var isCSV: Bool {
+        format == .csv
+    }
+
+    /// Keys the rows need in addition to `ContactsService.basicKeys`
+    static var keys: [CNKeyDescriptor] {
+        CNContact.summaryKeys
+    }
+
+    func validate() throws {
+        if output != nil && !isCSV {
+            throw ValidationError("--output requires --format csv")
+        }
+    }
+
+    /// Print the contacts as CSV, or write them to --output
+    func write(_ contacts: [CNContact], redaction: Redaction, displayID: (String) -> String, nameLocale: Locale?) throws {
+        let text = Self.csv(contacts, redaction: redaction, displayID: displayID, nameLocale: nameLocale)
+        if let output {
+            try text.write(to: URL(fileURLWithPath: output), atomically: true, encoding: .utf8)
+        } else {
+            print(text, terminator: "")
+        }
+    }
+
+    private static func csv(_ contacts: [CNContact], redaction: Redaction, displayID: (String) -> String, nameLocale: Locale?) -> String {
+        var lines = [
+            CSV.row(["Name", "First Name", "Last Name", "Organization", "Phone", "Email", "Other Phones", "Other Emails", "ID"]),
+        ]
+        for contact in contacts {
+            // The primary value comes first, as in --summary
+            let phones = LabelOrder.sortLabeledByPreference(contact.phoneNumbers, order: LabelOrder.defaultOrder)
+                .map { redaction.phone($0.value.stringValue) }
+            let emails = LabelOrder.sortLabeledByPreference(contact.emailAddresses, order: LabelOrder.defaultOrder)
+                .map { redaction.email($0.value as String) }
+            lines.append(CSV.row([
+                contact.fullName(locale: nameLocale),
+                contact.givenName,
+                contact.familyName,
+                contact.organizationName,
+                phones.first ?? "",
+                emails.first ?? "",
+                phones.dropFirst().joined(separator: "; "),
+                emails.dropFirst().joined(separator: "; "),
+                displayID(contact.identifier),
+            ]))
+        }
+        return lines.joined(separator: "\n") + "\n"
+    }
+}
@@ -0,0 +1,14 @@
+import ArgumentParser
+import Foundation
+
+/// `--compact` for logs and scripts: `--json` output on a single line instead of
+/// indented, which is smaller and quicker to parse. Indented output stays the default.
+struct CompactOption: ParsableArguments {
+    @Flag(name: .long, help: "Print --json output on a single line instead of indented")
+    var compact = false
+
+    /// Options for writing JSON output, on top of `base` (e.g. .sortedKeys)
+    func writingOptions(_ base: JSONSerialization.WritingOptions = []) -> JSONSerialization.WritingOptions {
+        compact ? base : base.union(.prettyPrinted)
+    }
+}
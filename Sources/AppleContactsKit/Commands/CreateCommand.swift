@@ -0,0 +1,165 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct Create: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "Add a new contact",
+        discussion: """
+            Create a contact from the given fields. At least a name or an
+            organization is required.
+
+            With --check-duplicates, existing contacts sharing an email address,
+            a phone number (compared after normalization) or the full name are
+            looked up first. If one is found it is printed and nothing is created,
+            unless --force is given. --dry-run runs the same check and shows what
+            would be created without saving anything.
+
+            Examples:
+              apple-contacts create --first-name Erik --last-name Fisher --email erik@acme.com
+              apple-contacts create --first-name Erik --last-name Fisher --phone "+47 900 00 000" --check-duplicates
+              apple-contacts create --org "Acme" --phone "+47 22 00 00 00" --check-duplicates --dry-run
+            """
+    )
+
+    @Option(name: .long, help: "First name")
+    var firstName: String?
+
+    @Option(name: .long, help: "Last name")
+    var lastName: String?
+
+    @Option(name: .long, help: "Organization")
+    var org: String?
+
+    @Option(name: .long, help: "Job title")
+    var jobTitle: String?
+
+    @Option(name: .long, help: "Email address (repeat for several)")
+    var email: [String] = []
+
+    @Option(name: .long, help: "Phone number, labeled mobile (repeat for several)")
+    var phone: [String] = []
+
+    @Option(name: .long, help: "Birthday (YYYY-MM-DD, MM-DD, or e.g. \"March 14\")")
+    var birthday: String?
+
+    @Flag(name: .long, help: "Refuse to create the contact when a likely duplicate exists")
+    var checkDuplicates = false
+
+    @Flag(name: .long, help: "With --check-duplicates, create the contact even if a duplicate is found")
+    var force = false
+
+    @Flag(name: .long, help: "Show what would be created (and any duplicate) without saving")
+    var dryRun = false
+
+    @Flag(name: .shortAndLong, help: "Output as JSON")
+    var json = false
+
+    @OptionGroup var options: WriteOptions
+
+    func validate() throws {
+        let hasName = [firstName, lastName, org].contains { !($0 ?? "").trimmingCharacters(in: .whitespaces).isEmpty }
+        if !hasName {
+            throw ValidationError("Please provide --first-name, --last-name or --org")
+        }
+        if force && !checkDuplicates {
+            throw ValidationError("--force only applies with --check-duplicates")
+        }
+        if let birthday {
+            _ = try BirthdayParser.parse(birthday)
+        }
+    }
+
+    func run() throws {
+        let service = ContactsService()
+        let log = options.logger(command: "create")
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        let contact = try newContact()
+        let name = contact.displayName.isEmpty ? "(no name)" : contact.displayName
+
+        var duplicate: (contact: CNContact, reason: String)?
+        if checkDuplicates {
+            let existing = try service.fetchAll(keysToFetch: ContactsService.fullKeys)
+            let matcher = ImportMatcher(existing: existing, region: options.resolvedPhoneRegion)
+            duplicate = matcher.match(contact, alwaysCompareNames: true)
+            log.debug("Checked \(existing.count) contact(s) for duplicates", ["duration_ms": log.elapsedMilliseconds])
+        }
+
+        let created = !dryRun && (duplicate == nil || force)
+        if created {
+            try service.addContact(contact)
+        }
+
+        if json {
+            printJSON(contact, duplicate: duplicate, created: created)
+        } else if options.quiet {
+            // A refused create is an error, so it is still reported
+            if let duplicate, !created, !dryRun {
+                FileHandle.standardError.write(Data("Not created: possible duplicate of \(options.displayID(duplicate.contact.identifier)), same \(duplicate.reason). Use --force to add \(name) anyway\n".utf8))
+            }
+        } else {
+            if let duplicate {
+                let existingName = duplicate.contact.displayName.isEmpty ? "(no name)" : duplicate.contact.displayName
+                print("Possible duplicate: \(existingName) (\(options.displayID(duplicate.contact.identifier))), same \(duplicate.reason)")
+            }
+            if created {
+                print("Created \(name) (\(options.displayID(contact.identifier)))")
+            } else if dryRun {
+                print("Dry run: would \(duplicate == nil || force ? "create" : "not create") \(name)")
+            } else {
+                print("Not created. Use --force to add \(name) anyway")
+            }
+        }
+
+        if duplicate != nil && !force && !dryRun {
+            throw ExitCode.failure
+        }
+    }
+
+    private func newContact() throws -> CNMutableContact {
+        let contact = CNMutableContact()
+        contact.givenName = firstName ?? ""
+        contact.familyName = lastName ?? ""
+        contact.organizationName = org ?? ""
+        contact.jobTitle = jobTitle ?? ""
+        if firstName == nil && lastName == nil {
+            contact.contactType = .organization
+        }
+        contact.emailAddresses = email.map { CNLabeledValue(label: CNLabelOther, value: $0 as NSString) }
+        contact.phoneNumbers = phone.map {
+            CNLabeledValue(label: CNLabelPhoneNumberMobile, value: CNPhoneNumber(stringValue: $0))
+        }
+        contact.birthday = try birthday.map(BirthdayParser.parse)
+        return contact
+    }
+
+    private func printJSON(_ contact: CNContact, duplicate: (contact: CNContact, reason: String)?, created: Bool) {
+        var data: [String: Any] = [
+            "name": contact.displayName,
+            "created": created,
+            "dryRun": dryRun,
+        ]
+        if created {
+            data["id"] = options.displayID(contact.identifier)
+        }
+        if let duplicate {
+            data["duplicate"] = [
+                "id": options.displayID(duplicate.contact.identifier),
+                "name": duplicate.contact.displayName,
+                "matchedBy": duplicate.reason,
+            ]
+        }
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: [.prettyPrinted, .sortedKeys]),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+}
@@ -0,0 +1,91 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct Delete: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "Delete a contact",
+        discussion: """
+            Delete a single contact identified by name or ID. A name must match
+            exactly one contact; use --id (see 'resolve') when it doesn't.
+
+            The contact is shown and you are asked to confirm (y/N). --force skips
+            the question, which is required when stdin isn't a terminal. The name
+            of the deleted contact is printed afterwards.
+
+            Examples:
+              apple-contacts delete "Erik Fisher"
+              apple-contacts delete --id ABC123...
+              apple-contacts delete --id ABC123... --force
+            """
+    )
+
+    @Argument(help: "Name of the contact to delete")
+    var name: String?
+
+    @Option(name: .long, help: "Contact ID (use if name is ambiguous)")
+    var id: String?
+
+    @Flag(name: .long, help: "Delete without asking for confirmation")
+    var force = false
+
+    @OptionGroup var idFormatOption: IDFormatOption
+
+    @OptionGroup var quietOption: QuietOption
+
+    func validate() throws {
+        if name == nil && id == nil {
+            throw ValidationError("Please provide a contact name or --id")
+        }
+        if name != nil && id != nil {
+            throw ValidationError("Please provide either a name or --id, not both")
+        }
+    }
+
+    func run() throws {
+        let service = ContactsService()
+        service.useStoredValues()
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        let found: CNContact?
+        if let id {
+            found = try service.getContact(id: id)
+        } else {
+            found = try service.getUniqueContact(name: name ?? "")
+        }
+        guard let contact = found else {
+            throw ContactsError.contactNotFound
+        }
+
+        if !force {
+            guard Terminal.canPrompt else {
+                throw ValidationError("Can't ask for confirmation without a terminal; use --force")
+            }
+            if !confirm(contact) {
+                if !quietOption.quiet {
+                    print("Not deleted")
+                }
+                return
+            }
+        }
+
+        try service.deleteContact(contact)
+
+        if !quietOption.quiet {
+            let displayName = contact.displayName.isEmpty ? "(no name)" : contact.displayName
+            print("Deleted \(displayName) (\(idFormatOption.displayID(contact.identifier)))")
+        }
+    }
+
+    /// Ask before deleting. The question goes to stderr, keeping stdout for the result
+    private func confirm(_ contact: CNContact) -> Bool {
+        let summary = contact.summaryLine()
+        let answer = Terminal.ask("Delete \(summary.isEmpty ? "(no name)" : summary)? [y/N] ")?.lowercased()
+        return answer == "y" || answer == "yes"
+    }
+}
@@ -0,0 +1,99 @@
+import ArgumentParser
+import Foundation
+
+struct Diff: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "Compare two JSON exports",
+        discussion: """
+            Report contacts added, removed and modified between two files written
+            by 'export --output file.json', matched by contact ID. Modified contacts
+            list each changed field. The live database is not read.
+
+            Examples:
+              apple-contacts export --all --output before.json
+              apple-contacts diff before.json after.json
+              apple-contacts diff before.json after.json --json
+            """
+    )
+
+    @Argument(help: "Older export file")
+    var old: String
+
+    @Argument(help: "Newer export file")
+    var new: String
+
+    @Flag(name: .shortAndLong, help: "Output as JSON")
+    var json = false
+
+    @OptionGroup var idFormatOption: IDFormatOption
+
+    @OptionGroup var quietOption: QuietOption
+
+    func run() throws {
+        let diff = ExportDiff(old: try ExportDiff.load(old), new: try ExportDiff.load(new))
+
+        if json {
+            printJSON(diff)
+        } else if !quietOption.quiet {
+            printReport(diff)
+        }
+    }
+
+    private func printReport(_ diff: ExportDiff) {
+        if diff.isEmpty {
+            print("No differences")
+            return
+        }
+
+        if !diff.added.isEmpty {
+            print("ADDED:")
+            for record in diff.added {
+                print("  + \(ExportDiff.name(of: record))  \(idFormatOption.displayID(ExportDiff.id(of: record)))")
+            }
+        }
+
+        if !diff.removed.isEmpty {
+            print(diff.added.isEmpty ? "REMOVED:" : "\nREMOVED:")
+            for record in diff.removed {
+                print("  - \(ExportDiff.name(of: record))  \(idFormatOption.displayID(ExportDiff.id(of: record)))")
+            }
+        }
+
+        if !diff.modified.isEmpty {
+            print(diff.added.isEmpty && diff.removed.isEmpty ? "MODIFIED:" : "\nMODIFIED:")
+            for contact in diff.modified {
+                print("  ~ \(contact.name)  \(idFormatOption.displayID(contact.id))")
+                for change in contact.changes {
+                    let before = change.old.isEmpty ? "(none)" : change.old
+                    let after = change.new.isEmpty ? "(none)" : change.new
+                    print("      \(change.field.padding(toLength: 12, withPad: " ", startingAt: 0)) \(before) -> \(after)")
+                }
+            }
+        }
+
+        print("\n\(diff.added.count) added, \(diff.removed.count) removed, \(diff.modified.count) modified")
+    }
+
+    private func printJSON(_ diff: ExportDiff) {
+        let summary = { (record: ExportDiff.Record) -> [String: String] in
+            ["id": idFormatOption.displayID(ExportDiff.id(of: record)), "name": ExportDiff.name(of: record)]
+        }
+        let data: [String: Any] = [
+            "added": diff.added.map(summary),
+            "removed": diff.removed.map(summary),
+            "modified": diff.modified.map { contact -> [String: Any] in
+                [
+                    "id": idFormatOption.displayID(contact.id),
+                    "name": contact.name,
+                    "changes": contact.changes.map { ["field": $0.field, "old": $0.old, "new": $0.new] },
+                ]
+            },
+        ]
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: [.prettyPrinted, .sortedKeys]),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+}
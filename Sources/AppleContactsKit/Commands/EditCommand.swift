@@ -0,0 +1,151 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct Edit: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "Change fields of an existing contact",
+        discussion: """
+            Update a contact identified by name or ID. A name must match exactly
+            one contact; use --id (see 'resolve') when it doesn't.
+
+            Only the fields given are changed; everything else is left as it is.
+            Pass an empty value (e.g. --department "") to clear a field. Changing
+            the note requires the Contacts notes entitlement.
+
+            --birthday accepts 1985-03-14, 03-14 (no year), "March 14" or
+            "14 March 1985". Birthdays without a year are stored as such, the way
+            Contacts.app does. Impossible dates like 02-30 are rejected; 02-29 is
+            allowed without a year.
+
+            Examples:
+              apple-contacts edit "Erik Fisher" --birthday 1985-03-14
+              apple-contacts edit "Erik Fisher" --birthday "March 14"
+              apple-contacts edit --id ABC123... --clear-birthday
+              apple-contacts edit "Erik Fisher" --job-title CTO --department ""
+              apple-contacts edit --id ABC123... --first-name Erik --last-name Fisher
+            """
+    )
+
+    @Argument(help: "Name of the contact to edit")
+    var name: String?
+
+    @Option(name: .long, help: "Contact ID (use if name is ambiguous)")
+    var id: String?
+
+    @Option(name: .long, help: "Set the first name")
+    var firstName: String?
+
+    @Option(name: .long, help: "Set the last name")
+    var lastName: String?
+
+    @Option(name: .long, help: "Set the organization")
+    var org: String?
+
+    @Option(name: .long, help: "Set the job title")
+    var jobTitle: String?
+
+    @Option(name: .long, help: "Set the department")
+    var department: String?
+
+    @Option(name: .long, help: "Replace the note (needs the Contacts notes entitlement)")
+    var note: String?
+
+    @Option(name: .long, help: "Set the birthday (YYYY-MM-DD, MM-DD, or e.g. \"March 14\")")
+    var birthday: String?
+
+    @Flag(name: .long, help: "Remove the birthday")
+    var clearBirthday = false
+
+    @OptionGroup var quietOption: QuietOption
+
+    func validate() throws {
+        if name == nil && id == nil {
+            throw ValidationError("Please provide a contact name or --id")
+        }
+        if birthday != nil && clearBirthday {
+            throw ValidationError("--birthday and --clear-birthday cannot be used together")
+        }
+        if birthday == nil && !clearBirthday && textChanges.isEmpty {
+            throw ValidationError("Nothing to change. Use a field option such as --job-title, or --birthday")
+        }
+        if let birthday {
+            _ = try BirthdayParser.parse(birthday)
+        }
+    }
+
+    func run() throws {
+        let service = ContactsService()
+        // Save exactly what is stored, apart from the edited fields
+        service.useStoredValues()
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        let found: CNContact?
+        if let id {
+            found = try service.getContact(id: id)
+        } else {
+            found = try service.getUniqueContact(name: name ?? "")
+        }
+        guard var fetched = found else {
+            throw ContactsError.contactNotFound
+        }
+        // The note isn't part of the usual keys, and saving needs every modified key fetched
+        if note != nil {
+            let withNote: CNContact?
+            do {
+                withNote = try service.getContacts(
+                    ids: [fetched.identifier],
+                    keysToFetch: ContactsService.fullKeys + [CNContactNoteKey as CNKeyDescriptor]
+                ).first
+            } catch where ContactsError.isNoteAccessError(error) {
+                throw ContactsError.notesUnavailable
+            }
+            // Deleted in the meantime; the first fetch lacks the note key, so it can't be used
+            guard let withNote else {
+                throw ContactsError.contactNotFound
+            }
+            fetched = withNote
+        }
+        guard let contact = fetched.mutableCopy() as? CNMutableContact else {
+            throw ContactsError.contactNotFound
+        }
+
+        var changed: [String] = []
+        for change in textChanges {
+            contact[keyPath: change.keyPath] = change.value
+            changed.append(change.value.isEmpty ? "\(change.field) removed" : "\(change.field) \"\(change.value)\"")
+        }
+        if birthday != nil || clearBirthday {
+            contact.birthday = try birthday.map(BirthdayParser.parse)
+            changed.append("birthday \(contact.birthdayString ?? "removed")")
+        }
+        try service.updateContact(contact)
+
+        if quietOption.quiet {
+            return
+        }
+        let displayName = contact.displayName.isEmpty ? "(no name)" : contact.displayName
+        print("Updated \(displayName): \(changed.joined(separator: ", "))")
+    }
+
+    /// Text fields given on the command line, in the order they are reported.
+    /// Options that weren't given are left out, so those fields keep their values.
+    private var textChanges: [(field: String, keyPath: ReferenceWritableKeyPath<CNMutableContact, String>, value: String)] {
+        let options: [(String, ReferenceWritableKeyPath<CNMutableContact, String>, String?)] = [
+            ("first name", \.givenName, firstName),
+            ("last name", \.familyName, lastName),
+            ("organization", \.organizationName, org),
+            ("job title", \.jobTitle, jobTitle),
+            ("department", \.departmentName, department),
+            ("note", \.note, note),
+        ]
+        return options.compactMap { field, keyPath, value in
+            value.map { (field, keyPath, $0) }
+        }
+    }
+}
@@ -0,0 +1,621 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct Export: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "Export contact as vCard",
+        discussion: """
+            Export a contact, every member of a group, or all contacts in vCard format.
+            Output goes to stdout by default, or to a file with --output.
+
+            With --group or --all, --dedupe skips cards whose content is identical to
+            one already written (e.g. the same person linked from two accounts).
+
+            --append adds to existing --output files instead of replacing them, e.g.
+            to combine several exports. CSV files keep a single header and JSON
+            arrays are merged. A file holding a different format is left alone.
+
+            CSV output has one row per contact, with all phone numbers (and all email
+            addresses) in one column. --multivalue expand writes one row per phone
+            number and email address instead, repeating the contact's other columns.
+
+            With --all --split-by group, one file per group is written to --output-dir.
+            Contacts in several groups appear in each group's file, and contacts in no
+            group go to ungrouped.vcf.
+
+            With --all --split-by contact, each contact gets its own .vcf file. A journal
+            in the directory records every finished card, so an interrupted export can
+            continue with --resume; --restart starts over instead. Files already in the
+            directory are never overwritten.
+
+            Examples:
+              apple-contacts export "John Doe"
+              apple-contacts export --id ABC123... --output john.vcf
+              apple-contacts export --group "Family" --dedupe --output family.vcf
+              apple-contacts export --group "Family" --vcard-group --output family.vcf
+              apple-contacts export --all --output everyone.vcf
+              apple-contacts export --all --output backup.vcf --output backup.csv
+              apple-contacts export --all --split-by group --output-dir backup/
+              apple-contacts export --all --split-by contact --output-dir cards/ --resume
+              apple-contacts export "John Doe" --with-photo --output john.vcf
+              apple-contacts export --all --fold-width 0 --output unfolded.vcf
+              apple-contacts export "John Doe" --format mecard
+              apple-contacts export "John Doe" --only name,phone --output john.vcf
+              apple-contacts export "John Doe" --exclude-fields note,birthday
+              apple-contacts export --all --format csv | head
+              apple-contacts export --all --csv-bom --output contacts.csv
+              apple-contacts export --all --format csv --multivalue expand
+              apple-contacts export --all --normalize --output everyone.vcf
+              apple-contacts export --all --ascii-only --format csv --output legacy.csv
+              apple-contacts export --group "Family" --append --output everyone.csv
+              apple-contacts export --all --output everyone.vcf --progress-to fd3 3>progress.jsonl
+              apple-contacts export --all --output everyone.vcf --progress-every 500
+            """
+    )
+
+    enum SplitBy: String, ExpressibleByArgument, CaseIterable {
+        case group
+        case contact
+    }
+
+    @Argument(help: "Contact name to export")
+    var name: String?
+
+    @Option(name: .long, help: "Contact ID (use if name is ambiguous)")
+    var id: String?
+
+    @Option(name: .long, help: "Export all members of this group")
+    var group: String?
+
+    @Flag(name: .long, help: "Export all contacts")
+    var all = false
+
+    @Option(name: .long, help: "With --all, write one file per group or per contact (group, contact)")
+    var splitBy: SplitBy?
+
+    @Option(name: .long, help: "Directory for --split-by files")
+    var outputDir: String?
+
+    @Flag(name: .long, help: "Continue an interrupted --split-by contact export, skipping cards already written")
+    var resume = false
+
+    @Flag(name: .long, help: "Ignore the journal of an earlier per-contact export and write every card again")
+    var restart = false
+
+    @Flag(name: .long, help: "Skip cards with identical content (group and --all exports)")
+    var dedupe = false
+
+    @Flag(name: .long, help: "Tag each card of a --group export with CATEGORIES:<group>, so other apps keep the grouping")
+    var vcardGroup = false
+
+    @Flag(name: .long, help: "Make sure the contact photo is included (adds PHOTO when missing)")
+    var withPhoto = false
+
+    @Option(name: .long, help: "Output format (vcard, json, csv, mecard). Default: from the --output extension, else vcard")
+    var format: ExportFormat?
+
+    @Option(name: .long, help: "Build minimal vCards with only these fields (name, nickname, org, title, phone, phones, email, emails, birthday, address, url)")
+    var only: String?
+
+    @Option(name: .long, help: "Leave these fields out of vCards (note, birthday, photo, address, phone, email, url, ...)")
+    var excludeFields: String?
+
+    @Option(name: .long, help: "Fold vCard lines longer than this many bytes (0 disables folding)")
+    var foldWidth = 75
+
+    @Option(
+        name: .shortAndLong,
+        help: "Output file path, or - for stdout (default: stdout). Repeat to write several files; the format follows the extension (.vcf, .json, .csv)"
+    )
+    var output: [String] = []
+
+    @Flag(name: .long, help: "Lowercase email domains in the exported data (the stored contacts are not changed)")
+    var normalize = false
+
+    @Flag(name: .long, help: "Export stored values as-is, without trimming and collapsing whitespace")
+    var raw = false
+
+    @Flag(
+        name: .long,
+        inversion: .prefixedNo,
+        help: "Sort each contact's phones, emails and addresses by label and value (default on)"
+    )
+    var stable = true
+
+    @Option(name: .long, help: "CSV layout for several phones or emails: wide (one row per contact, default) or expand (one row per value)")
+    var multivalue: CSVMultivalue?
+
+    @Flag(name: .long, help: "Transliterate text to plain ASCII (é → e, ß → ss) for systems that can't read anything else")
+    var asciiOnly = false
+
+    @Option(name: .long, help: "With --ascii-only, what to do with characters that have no ASCII form: replace (with ?, default) or drop")
+    var onUnmappable: ASCIIText.Unmappable?
+
+    @Flag(name: .long, help: "Start CSV output with a UTF-8 byte order mark, so Excel reads accented text correctly")
+    var csvBom = false
+
+    @Flag(name: .long, help: "Add to existing --output files instead of overwriting them")
+    var append = false
+
+    @Option(
+        name: .long,
+        help: ArgumentHelp(
+            "Write JSON progress events ({\"done\":120,\"total\":4000}) to a file or a file descriptor (fd3)",
+            discussion: "For group and --all exports. Events go to their own stream, so stdout and stderr are unchanged."
+        )
+    )
+    var progressTo: String?
+
+    @Option(name: .long, help: "For group and --all exports, print progress to stderr every N contacts")
+    var progressEvery: Int?
+
+    @OptionGroup var labelOptions: LabelOrderOptions
+
+    @OptionGroup var idFormatOption: IDFormatOption
+
+    @OptionGroup var quietOption: QuietOption
+
+    func validate() throws {
+        if dedupe && group == nil && !all {
+            throw ValidationError("--dedupe requires --group or --all")
+        }
+        if all && group != nil {
+            throw ValidationError("--all and --group cannot be used together")
+        }
+        if splitBy != nil && !all {
+            throw ValidationError("--split-by requires --all")
+        }
+        if splitBy != nil && outputDir == nil {
+            throw ValidationError("--split-by requires --output-dir")
+        }
+        if outputDir != nil && splitBy == nil {
+            throw ValidationError("--output-dir requires --split-by")
+        }
+        if outputDir != nil && !output.isEmpty {
+            throw ValidationError("--output-dir and --output cannot be used together")
+        }
+        if (resume || restart) && splitBy != .contact {
+            throw ValidationError("--resume and --restart require --split-by contact")
+        }
+        if resume && restart {
+            throw ValidationError("--resume and --restart cannot be used together")
+        }
+        if splitBy == .contact && dedupe {
+            throw ValidationError("--dedupe cannot be combined with --split-by contact")
+        }
+        if splitBy != nil, let format, format != .vcard {
+            throw ValidationError("--split-by only writes vCard files")
+        }
+        if let only {
+            _ = try MinimalVCard.parse(only)
+        }
+        if let excludeFields {
+            let excluded = try VCard.parseExcludedFields(excludeFields)
+            if withPhoto && excluded.contains("PHOTO") {
+                throw ValidationError("--with-photo and --exclude-fields photo cannot be used together")
+            }
+            if outputFormats.contains(where: { $0 != .vcard }) {
+                throw ValidationError("--exclude-fields only applies to vCard output")
+            }
+        }
+        if vcardGroup {
+            if group == nil {
+                throw ValidationError("--vcard-group requires --group")
+            }
+            if outputFormats.contains(where: { $0 != .vcard }) {
+                throw ValidationError("--vcard-group only applies to vCard output")
+            }
+        }
+        if multivalue != nil && !outputFormats.contains(.csv) {
+            throw ValidationError("--multivalue only applies to CSV output")
+        }
+        if append && (output.isEmpty || output.contains("-")) {
+            throw ValidationError("--append requires --output files (not stdout)")
+        }
+        if let progressEvery {
+            if progressEvery < 1 {
+                throw ValidationError("--progress-every must be at least 1")
+            }
+            if group == nil && !all {
+                throw ValidationError("--progress-every requires --group or --all")
+            }
+            if progressTo != nil {
+                throw ValidationError("--progress-every and --progress-to cannot be used together")
+            }
+        }
+        if let progressTo {
+            _ = try JSONProgressSink.parse(progressTo)
+            if group == nil && !all {
+                throw ValidationError("--progress-to requires --group or --all")
+            }
+        }
+        if onUnmappable != nil && !asciiOnly {
+            throw ValidationError("--on-unmappable requires --ascii-only")
+        }
+        if foldWidth != 0 && foldWidth < 5 {
+            throw ValidationError("--fold-width must be 0 (no folding) or at least 5")
+        }
+    }
+
+    func run() throws {
+        let service = ContactsService()
+        service.labelOrder = labelOptions.resolvedOrder
+        if let progressTo {
+            service.progress = try JSONProgressSink(target: progressTo)
+        } else if let progressEvery {
+            service.progress = TextProgressSink(every: progressEvery)
+        }
+        service.lowercaseEmails = normalize
+        service.normalizeWhitespace = !raw
+        service.stableOrder = stable
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        if let splitBy, let outputDir {
+            switch splitBy {
+            case .group:
+                try exportSplitByGroup(to: outputDir, service: service)
+            case .contact:
+                try exportPerContact(to: outputDir, service: service)
+            }
+            return
+        }
+
+        if streamsAll {
+            try exportAllStreaming(service: service)
+            return
+        }
+
+        let exported: [ExportedContact]
+        var category: String?
+
+        if all {
+            exported = try service.exportAllVCards()
+        } else if let groupName = group {
+            guard let group = try service.getGroup(name: groupName) else {
+                throw ContactsError.groupNotFound
+            }
+            exported = try service.exportGroupVCards(group)
+            category = vcardGroup ? group.name : nil
+        } else {
+            var contact: CNContact?
+
+            if let id = id {
+                contact = try service.getContact(id: id)
+            } else if let name = name {
+                contact = try service.getContact(name: name)
+            } else {
+                throw ValidationError("Please provide a contact name, --id, --group or --all")
+            }
+
+            guard let contact else {
+                throw ContactsError.contactNotFound
+            }
+            exported = [(contact, try service.exportVCardString(contact: contact))]
+        }
+
+        // The category goes on after --only, which rebuilds each card from the contact
+        let categorized = try minimal(exported).map { card in
+            (card.contact, category.map { VCard.addCategory(card.vcard, $0) } ?? card.vcard)
+        }
+        let (records, skipped) = deduplicated(refolded(stripped(try ensuringPhotos(categorized, service: service))))
+        try write(records, skipped: skipped)
+    }
+
+    /// Apply --with-photo to several cards
+    private func ensuringPhotos(_ exported: [ExportedContact], service: ContactsService) throws -> [ExportedContact] {
+        guard withPhoto else { return exported }
+        return try exported.map { ($0.contact, try ensuringPhoto($0.vcard, id: $0.contact.identifier, service: service)) }
+    }
+
+    /// Add the contact's image to a card that lacks a PHOTO property
+    private func ensuringPhoto(_ vcard: String, id: String, service: ContactsService) throws -> String {
+        guard !VCard.hasProperty(vcard, named: "PHOTO"),
+              let photo = try service.getContactPhoto(id: id)
+        else {
+            return vcard
+        }
+        return VCard.injectPhoto(vcard, data: photo, type: VCard.imageType(of: photo))
+    }
+
+    /// Replace Apple's full cards with minimal ones when --only is set
+    private func minimal(_ exported: [ExportedContact]) throws -> [ExportedContact] {
+        guard let only else { return exported }
+        let fields = try MinimalVCard.parse(only)
+        return exported.map { ($0.contact, MinimalVCard.build($0.contact, fields: fields)) }
+    }
+
+    /// --all to vCard only: cards are written as they are serialized instead of collected
+    /// first, so large address books don't have to fit in memory as one string
+    private var streamsAll: Bool {
+        all && !append && outputFormats.allSatisfy { $0 == .vcard }
+    }
+
+    /// Format of each output target: --format, else the file's extension, else vCard
+    private var outputFormats: [ExportFormat] {
+        output.isEmpty ? [format ?? .vcard] : output.map { format ?? ($0 == "-" ? .vcard : ExportFormat(path: $0) ?? .vcard) }
+    }
+
+    /// Remove the --exclude-fields properties from every card
+    private func stripped(_ exported: [ExportedContact]) -> [ExportedContact] {
+        guard let excludeFields, let properties = try? VCard.parseExcludedFields(excludeFields) else {
+            return exported
+        }
+        return exported.map { ($0.contact, VCard.stripProperties($0.vcard, properties)) }
+    }
+
+    /// Fold every card's lines at --fold-width. --ascii-only is applied first, so the
+    /// folds are measured on the text that is written.
+    private func refolded(_ exported: [ExportedContact]) -> [ExportedContact] {
+        exported.map { ($0.contact, VCard.refold(transliterated($0.vcard), width: foldWidth)) }
+    }
+
+    /// Apply --ascii-only
+    private func transliterated(_ content: String) -> String {
+        asciiOnly ? ASCIIText.transliterate(content, unmappable: onUnmappable ?? .replace) : content
+    }
+
+    /// Drop cards with identical content when --dedupe is set
+    private func deduplicated(_ exported: [ExportedContact]) -> (records: [ExportedContact], skipped: Int) {
+        guard dedupe else { return (exported, 0) }
+
+        var seen = Set<String>()
+        let unique = exported.filter { seen.insert(VCard.contentHash($0.vcard)).inserted }
+        return (unique, exported.count - unique.count)
+    }
+
+    /// Write to stdout (no --output, or "-") and to each --output file, in --format or
+    /// the format matching the file's extension
+    private func write(_ records: [ExportedContact], skipped: Int) throws {
+        let targets = output.isEmpty ? ["-"] : output
+
+        let dataOnStdout = targets.contains("-")
+
+        let single = !all && group == nil
+        for target in targets {
+            if target == "-" {
+                let format = self.format ?? .vcard
+                print(Self.withBOM(transliterated(format.encode(records, multivalue: multivalue ?? .wide, idFormat: idFormatOption.idFormat)), format: format, enabled: csvBom), terminator: "")
+                continue
+            }
+
+            let format = self.format ?? ExportFormat(path: target) ?? .vcard
+            let url = URL(fileURLWithPath: target)
+            // Only the new records are transliterated; an appended file keeps its text as is
+            var content = transliterated(format.encode(records, multivalue: multivalue ?? .wide, idFormat: idFormatOption.idFormat))
+            let appending = append && FileManager.default.fileExists(atPath: target)
+            if appending {
+                let existing = try String(contentsOf: url, encoding: .utf8)
+                guard let combined = format.appending(encoded: content, to: existing, multivalue: multivalue ?? .wide) else {
+                    throw ContactsError.appendFormatMismatch(target, format: format.rawValue)
+                }
+                content = combined
+            }
+            try Self.withBOM(content, format: format, enabled: csvBom).write(to: url, atomically: true, encoding: .utf8)
+
+            let verb = appending ? "Appended" : "Exported"
+            var summary = single ? "\(verb) to \(target)" : "\(verb) \(records.count) contact(s) to \(target)"
+            if targets.count > 1 {
+                summary += " (\(format.rawValue))"
+            }
+            status(summary, dataOnStdout: dataOnStdout)
+        }
+
+        if dedupe {
+            status("Skipped \(skipped) duplicate(s)", dataOnStdout: dataOnStdout)
+        }
+    }
+
+    /// Write every contact's card to stdout and each --output file as soon as it is ready.
+    /// Files are written under a temporary name and moved into place at the end, so an
+    /// interrupted export leaves any previous file untouched.
+    private func exportAllStreaming(service: ContactsService) throws {
+        let targets = output.isEmpty ? ["-"] : output
+        let dataOnStdout = targets.contains("-")
+
+        var files: [(target: String, temporary: URL, handle: FileHandle)] = []
+        func discardFiles() {
+            for file in files {
+                try? file.handle.close()
+                try? FileManager.default.removeItem(at: file.temporary)
+            }
+        }
+        for target in targets where target != "-" {
+            let url = URL(fileURLWithPath: target)
+            let temporary = url.deletingLastPathComponent().appendingPathComponent(".\(url.lastPathComponent).partial")
+            guard FileManager.default.createFile(atPath: temporary.path, contents: nil),
+                  let handle = FileHandle(forWritingAtPath: temporary.path)
+            else {
+                discardFiles()
+                throw ValidationError("Cannot write to \(target)")
+            }
+            files.append((target, temporary, handle))
+        }
+
+        var hashes = Set<String>()
+        var written = 0
+        var skipped = 0
+        do {
+            try service.forEachVCard { contact, vcard in
+                guard let card = refolded(stripped(try ensuringPhotos(try minimal([(contact, vcard)]), service: service))).first else {
+                    return
+                }
+                if dedupe && !hashes.insert(VCard.contentHash(card.vcard)).inserted {
+                    skipped += 1
+                    return
+                }
+                let data = Data(ExportFormat.vcard.encode([card]).utf8)
+                if dataOnStdout {
+                    FileHandle.standardOutput.write(data)
+                }
+                for file in files {
+                    file.handle.write(data)
+                }
+                written += 1
+            }
+            for file in files {
+                try file.handle.close()
+                let url = URL(fileURLWithPath: file.target)
+                // Swapped in one step, so the previous export never goes missing
+                if FileManager.default.fileExists(atPath: url.path) {
+                    _ = try FileManager.default.replaceItemAt(url, withItemAt: file.temporary)
+                } else {
+                    try FileManager.default.moveItem(at: file.temporary, to: url)
+                }
+            }
+        } catch {
+            discardFiles()
+            throw error
+        }
+
+        for file in files {
+            status("Exported \(written) contact(s) to \(file.target)", dataOnStdout: dataOnStdout)
+        }
+        if dedupe {
+            status("Skipped \(skipped) duplicate(s)", dataOnStdout: dataOnStdout)
+        }
+    }
+
+    /// Report progress to the user, unless --quiet. Goes to stderr when stdout
+    /// carries the exported data, to keep that stream clean
+    private func status(_ message: String, dataOnStdout: Bool = false) {
+        if quietOption.quiet {
+            return
+        }
+        if dataOnStdout {
+            FileHandle.standardError.write(Data("\(message)\n".utf8))
+        } else {
+            print(message)
+        }
+    }
+
+    /// Prefix CSV output with a UTF-8 byte order mark when `enabled` (--csv-bom).
+    /// Content that already starts with one, like an appended file, is left alone.
+    static func withBOM(_ content: String, format: ExportFormat, enabled: Bool) -> String {
+        guard enabled, format == .csv, !content.hasPrefix(ExportFormat.byteOrderMark) else {
+            return content
+        }
+        return ExportFormat.byteOrderMark + content
+    }
+
+    private func exportSplitByGroup(to directory: String, service: ContactsService) throws {
+        let groups = try service.listGroups().sorted {
+            $0.name.localizedStandardCompare($1.name) == .orderedAscending
+        }
+        let everyone = refolded(stripped(try ensuringPhotos(try minimal(service.exportAllVCards()), service: service)))
+        let memberIDs = try service.memberIDs(of: groups)
+        let grouped = memberIDs.values.reduce(into: Set<String>()) { $0.formUnion($1) }
+
+        var files: [(name: String, cards: [ExportedContact])] = groups.map { group in
+            let members = memberIDs[group.identifier] ?? []
+            return (group.name, everyone.filter { members.contains($0.contact.identifier) })
+        }
+        files.append(("ungrouped", everyone.filter { !grouped.contains($0.contact.identifier) }))
+
+        let directoryURL = URL(fileURLWithPath: directory)
+        try FileManager.default.createDirectory(at: directoryURL, withIntermediateDirectories: true, attributes: nil)
+
+        var usedNames = Set<String>()
+        var totalSkipped = 0
+        status("Exporting to \(directory)")
+        for file in files where !file.cards.isEmpty {
+            let filename = Self.uniqueFilename(for: file.name, used: &usedNames)
+            let (cards, skipped) = deduplicated(file.cards)
+            try ExportFormat.vcard.encode(cards)
+                .write(to: directoryURL.appendingPathComponent(filename), atomically: true, encoding: .utf8)
+            totalSkipped += skipped
+            status("  \(filename.padding(toLength: 30, withPad: " ", startingAt: 0)) \(cards.count) contact(s)")
+        }
+
+        if dedupe {
+            status("\nSkipped \(totalSkipped) duplicate(s)")
+        }
+    }
+
+    /// Write each contact to its own file, journaling progress so the export can be resumed
+    private func exportPerContact(to directory: String, service: ContactsService) throws {
+        let directoryURL = URL(fileURLWithPath: directory)
+        try FileManager.default.createDirectory(at: directoryURL, withIntermediateDirectories: true, attributes: nil)
+
+        let journal = ExportJournal(directory: directoryURL)
+        if journal.exists && !resume && !restart {
+            throw ValidationError("\(directory) holds an unfinished export. Use --resume to continue it or --restart to start over")
+        }
+        if resume && journal.exists {
+            try journal.load()
+        } else {
+            try journal.reset()
+        }
+
+        // Names taken by the earlier run stay taken, so resumed files don't overwrite them
+        var usedNames = Set(journal.entries.values.map { $0.lowercased() })
+        let skipped = journal.entries.count
+        var written = 0
+
+        status("Exporting to \(directory)")
+        if skipped > 0 {
+            status("Resuming: \(skipped) contact(s) already exported")
+        }
+        try service.forEachVCard(skipping: Set(journal.entries.keys)) { contact, vcard in
+            guard let card = refolded(stripped(try ensuringPhotos(try minimal([(contact, vcard)]), service: service))).first?.vcard else {
+                return
+            }
+            let filename = Self.cardFilename(for: contact.displayName, card: card, in: directoryURL, used: &usedNames)
+            try card.write(to: directoryURL.appendingPathComponent(filename), atomically: true, encoding: .utf8)
+            try journal.record(id: contact.identifier, filename: filename)
+            written += 1
+        }
+
+        try journal.remove()
+        status("Exported \(written) contact(s)" + (skipped > 0 ? " (\(skipped + written) in total)" : ""))
+    }
+
+    /// Pick the file for one contact's card. Files already in the directory are never
+    /// overwritten, with one exception: a file holding exactly this card was written by an
+    /// interrupted run just before its journal entry, so it is reused rather than duplicated.
+    /// Contacts without a usable name get files named "contact".
+    static func cardFilename(for name: String, card: String, in directory: URL, used: inout Set<String>) -> String {
+        while true {
+            let filename = uniqueFilename(for: name, fallback: "contact", used: &used)
+            let url = directory.appendingPathComponent(filename)
+            guard FileManager.default.fileExists(atPath: url.path) else {
+                return filename
+            }
+            if (try? String(contentsOf: url, encoding: .utf8)) == card {
+                return filename
+            }
+        }
+    }
+
+    /// Turn a group or contact name into a safe, unique `.vcf` filename. An empty name, or
+    /// one that would make a hidden file, gets `fallback` in front.
+    static func uniqueFilename(for name: String, fallback: String = "group", used: inout Set<String>) -> String {
+        // C0 controls, DEL and C1 controls, plus the bidi embeddings, overrides and isolates,
+        // which can make a listed name read differently from the bytes on disk. Not all of
+        // .controlCharacters: that also covers the zero-width joiners in emoji sequences
+        // (👨‍👩‍👧) and the left-to-right and right-to-left marks, which are kept.
+        let controls = CharacterSet(charactersIn: "\u{0}"..."\u{1F}")
+            .union(CharacterSet(charactersIn: "\u{7F}"..."\u{9F}"))
+            .union(CharacterSet(charactersIn: "\u{202A}"..."\u{202E}"))
+            .union(CharacterSet(charactersIn: "\u{2066}"..."\u{2069}"))
+        let unsafe = CharacterSet(charactersIn: "/\\:*?\"<>|").union(controls)
+        var base = String(name.unicodeScalars.map { unsafe.contains($0) ? "_" : Character($0) })
+            .trimmingCharacters(in: .whitespaces)
+        if base.isEmpty || base.hasPrefix(".") {
+            base = fallback + base
+        }
+
+        var filename = "\(base).vcf"
+        var counter = 2
+        while !used.insert(filename.lowercased()).inserted {
+            filename = "\(base) (\(counter)).vcf"
+            counter += 1
+        }
+        return filename
+    }
+}
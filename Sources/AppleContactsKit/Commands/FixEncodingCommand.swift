@@ -0,0 +1,123 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct FixEncoding: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "fix-encoding",
+        abstract: "Repair double-encoded text in contact fields",
+        discussion: """
+            Find names, organizations and other text fields containing
+            double-encoded UTF-8 (e.g. "JosÃ©" instead of "José"), usually left
+            behind by a bad import, and repair them.
+
+            Use --dry-run to review the changes before saving them.
+
+            Normally the first contact that can't be saved stops the run. With
+            --report, failures are recorded and the rest are still fixed;
+            --retry-failed re-attempts only the contacts a report lists as failed.
+
+            Examples:
+              apple-contacts fix-encoding --dry-run
+              apple-contacts fix-encoding
+              apple-contacts fix-encoding --report fix-report.json
+              apple-contacts fix-encoding --retry-failed fix-report.json
+            """
+    )
+
+    @Flag(name: .long, help: "Show what would change without saving")
+    var dryRun = false
+
+    @Option(name: .long, help: "Write each contact's outcome to this JSON file and continue past failures")
+    var report: String?
+
+    @Option(name: .long, help: "Re-attempt only the contacts that failed in this --report file")
+    var retryFailed: String?
+
+    @OptionGroup var quietOption: QuietOption
+
+    func validate() throws {
+        if dryRun && (report != nil || retryFailed != nil) {
+            throw ValidationError("--dry-run cannot be combined with --report or --retry-failed")
+        }
+    }
+
+    func run() throws {
+        let service = ContactsService()
+        // Save exactly what is stored, apart from the repaired fields
+        service.useStoredValues()
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        var contacts = try service.fetchAll(keysToFetch: Linter.requiredKeys)
+        if let retryFailed {
+            let failedIDs = Set(try OperationReport.load(retryFailed, command: "fix-encoding").failed.compactMap(\.id))
+            contacts = contacts.filter { failedIDs.contains($0.identifier) }
+        }
+
+        // With a report, a contact that can't be saved is recorded and the rest are still fixed
+        let keepGoing = report != nil || retryFailed != nil
+        var operations = OperationReport(command: "fix-encoding")
+        var fixedFields = 0
+        var fixedContacts = 0
+
+        for (index, contact) in contacts.enumerated() {
+            let issues = Linter.encodingIssues(for: contact)
+            if issues.isEmpty { continue }
+
+            say(contact.fullName)
+            for issue in issues {
+                say("  \(issue.field.padding(toLength: 12, withPad: " ", startingAt: 0)) \(issue.value) -> \(issue.suggestion ?? issue.value)")
+            }
+
+            if !dryRun {
+                let mutable = contact.mutableCopy() as! CNMutableContact
+                for field in CNContact.textFields where contact.isKeyAvailable(field.key) {
+                    if let fixed = Mojibake.fix(mutable[keyPath: field.write]) {
+                        mutable[keyPath: field.write] = fixed
+                    }
+                }
+                do {
+                    try service.updateContact(mutable)
+                    operations.recordSuccess(index: index, id: contact.identifier, name: contact.fullName, action: "fixed")
+                } catch {
+                    guard keepGoing else {
+                        throw error
+                    }
+                    operations.recordFailure(index: index, id: contact.identifier, name: contact.fullName, action: "fixed", error: error)
+                    FileHandle.standardError.write(Data("Could not save \(contact.fullName): \(operations.failed.last?.error ?? "")\n".utf8))
+                    continue
+                }
+            }
+
+            fixedFields += issues.count
+            fixedContacts += 1
+        }
+
+        if let report {
+            try operations.save(to: report)
+        }
+
+        if fixedContacts == 0 && operations.failed.isEmpty {
+            say("No encoding problems found")
+        } else if dryRun {
+            say("\nWould fix \(fixedFields) field(s) in \(fixedContacts) contact(s). Run without --dry-run to save.")
+        } else {
+            say("\nFixed \(fixedFields) field(s) in \(fixedContacts) contact(s)")
+        }
+        if !operations.failed.isEmpty {
+            FileHandle.standardError.write(Data("\(operations.failed.count) contact(s) could not be saved\n".utf8))
+            throw ExitCode.failure
+        }
+    }
+
+    private func say(_ line: String) {
+        if !quietOption.quiet {
+            print(line)
+        }
+    }
+}
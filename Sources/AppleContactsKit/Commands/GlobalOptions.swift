@@ -0,0 +1,179 @@
+import ArgumentParser
+import Foundation
+
+/// Options shared by all commands that read contact data
+struct GlobalOptions: ParsableArguments {
+    @OptionGroup var phoneRegionOption: PhoneRegionOption
+
+    @Flag(name: .long, help: "Show stored values as-is, without trimming and collapsing whitespace")
+    var raw = false
+
+    @Flag(
+        name: .long,
+        inversion: .prefixedNo,
+        help: "Sort each contact's phones, emails and addresses by label and value (default on)"
+    )
+    var stable = true
+
+    @Flag(name: .long, help: "Show email addresses with the domain lowercased (Erik.Fisher@acme.com)")
+    var lowercaseEmails = false
+
+    @Flag(name: .long, help: "Show email addresses fully lowercased (implies --lowercase-emails)")
+    var lowercaseWholeEmails = false
+
+    @Option(
+        name: .long,
+        help: ArgumentHelp(
+            "Locale for dates, names and sorting (e.g. nb-NO, en-US). Default: the system locale",
+            discussion: "Affects how dates are written in human-readable output, how names are composed and how contacts and group names are ordered."
+        )
+    )
+    var locale: String?
+
+    @Option(name: .long, help: "How to write dates in human-readable output (locale, iso)")
+    var dateFormat: DateDisplay.Style = .locale
+
+    @OptionGroup var idFormatOption: IDFormatOption
+
+    @Flag(name: .long, help: "Disable colored output (also honors NO_COLOR)")
+    var noColor = false
+
+    @Option(
+        name: .long,
+        help: ArgumentHelp(
+            "Mask these fields in displayed output: phones, emails, addresses, birthdays or all",
+            discussion: "For screen sharing. Exported files always contain the real values."
+        )
+    )
+    var redact: String?
+
+    @Option(
+        name: .long,
+        help: ArgumentHelp(
+            "Shell command that formats each contact; it gets the contact's JSON on stdin",
+            discussion: "Runs once per contact, e.g. --format-command 'jq -r .name'. A failing run is reported and the rest continue."
+        )
+    )
+    var formatCommand: String?
+
+    @Option(
+        name: .long,
+        help: ArgumentHelp(
+            "Print only the values selected by a JSONPath expression from the JSON output",
+            discussion: "E.g. '$.phones[0].value' for show, or '$[*].name' for search and list, whose root is an array. Supports .key, ['key'], [n] and [*]."
+        )
+    )
+    var jsonpath: String?
+
+    @OptionGroup var logOptions: LogOptions
+
+    @OptionGroup var quietOption: QuietOption
+
+    /// --quiet: skip human-readable output
+    var quiet: Bool {
+        quietOption.quiet
+    }
+
+    @Flag(
+        name: .long,
+        help: ArgumentHelp(
+            "Hold back stdout and write it all at once when the command ends",
+            discussion: "Keeps stdout in one piece after all stderr diagnostics, e.g. with --verbose --json under capture tools."
+        )
+    )
+    var bufferOutput = false
+
+    /// Contacts service configured from these options. Also starts --buffer-output buffering,
+    /// as commands create the service before printing anything.
+    func makeService() -> ContactsService {
+        if bufferOutput {
+            OutputBuffer.start()
+        }
+        let service = ContactsService()
+        service.normalizeWhitespace = !raw
+        service.stableOrder = stable
+        service.lowercaseEmails = lowercaseEmails || lowercaseWholeEmails
+        service.lowercaseWholeEmails = lowercaseWholeEmails
+        service.locale = resolvedLocale
+        service.sortsByLocale = locale != nil
+        return service
+    }
+
+    /// Logger for a command, configured from --verbose and --log-json
+    func logger(command: String) -> Logger {
+        logOptions.logger(command: command)
+    }
+
+    /// Fields to mask in displayed output (--redact)
+    var redaction: Redaction {
+        (try? redact.map(Redaction.init(parsing:))) ?? .none
+    }
+
+    /// Formatter for --format-command, if given
+    func externalFormatter(log: Logger) -> ExternalFormatter? {
+        formatCommand.map { ExternalFormatter(command: $0, log: log) }
+    }
+
+    /// Parsed --jsonpath, if given
+    var jsonPath: JSONPath? {
+        try? jsonpath.map(JSONPath.init(parsing:))
+    }
+
+    /// Print the values --jsonpath selects from a command's JSON output
+    func printJSONPath(_ path: JSONPath, in root: Any) throws {
+        for line in try path.lines(in: root) {
+            print(line)
+        }
+    }
+
+    /// --locale, or the system locale
+    var resolvedLocale: Locale {
+        locale.map { Locale(identifier: Locale.canonicalIdentifier(from: $0)) } ?? .current
+    }
+
+    /// Locale that composes names in output: --locale, or nil for the order set in Contacts
+    var nameLocale: Locale? {
+        locale == nil ? nil : resolvedLocale
+    }
+
+    /// Date rendering for human-readable output (--locale, --date-format)
+    var dateDisplay: DateDisplay {
+        DateDisplay(locale: resolvedLocale, style: dateFormat)
+    }
+
+    /// How contact IDs are printed (--id-format)
+    var idFormat: IDFormat {
+        idFormatOption.idFormat
+    }
+
+    /// A contact ID as printed with --id-format
+    func displayID(_ id: String) -> String {
+        idFormatOption.displayID(id)
+    }
+
+    /// Whether ANSI colors should be used for this run
+    var useColor: Bool {
+        Terminal.colorEnabled(noColor: noColor)
+    }
+
+    /// Region used to normalize phone numbers: --phone-region, $APPLE_CONTACTS_PHONE_REGION,
+    /// the config file, then the system region
+    var resolvedPhoneRegion: String? {
+        phoneRegionOption.resolvedPhoneRegion
+    }
+
+    func validate() throws {
+        if let redact {
+            _ = try Redaction(parsing: redact)
+        }
+        if let jsonpath {
+            _ = try JSONPath(parsing: jsonpath)
+            if formatCommand != nil {
+                throw ValidationError("--jsonpath and --format-command cannot be used together")
+            }
+        }
+        if let locale, !DateDisplay.isKnownLocale(locale) {
+            throw ValidationError("Unknown locale '\(locale)'. Use an identifier such as nb-NO, en-US or de")
+        }
+    }
+}
@@ -0,0 +1,182 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct Groups: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "List contact groups",
+        discussion: """
+            List contact groups and their member counts.
+            Groups with no members are hidden unless --include-empty is given.
+
+            A group whose members can't be read (this happens with some large
+            or smart groups) is retried once, then shown with count "?" (null
+            in JSON) instead of looking empty. Use --strict-counts to fail
+            instead.
+
+            --contains-id and --contains-name list only the groups a given contact
+            belongs to.
+
+            Examples:
+              apple-contacts groups
+              apple-contacts groups --include-empty
+              apple-contacts groups --empty-only
+              apple-contacts groups --json
+              apple-contacts groups --strict-counts
+              apple-contacts groups --concurrency 8
+              apple-contacts groups --contains-name "Erik Fisher"
+              apple-contacts groups --contains-id ABC123... --json
+            """
+    )
+
+    @Flag(name: .long, help: "Also list groups with no members")
+    var includeEmpty = false
+
+    @Flag(name: .long, help: "Only list groups with no members")
+    var emptyOnly = false
+
+    @Flag(name: .long, help: "Fail if a group's members can't be counted, instead of showing '?'")
+    var strictCounts = false
+
+    @Option(name: .long, help: "Only list groups that contain the contact with this ID")
+    var containsId: String?
+
+    @Option(name: .long, help: "Only list groups that contain this contact (the name must match exactly one)")
+    var containsName: String?
+
+    @Option(name: .long, help: "Count this many groups at a time (default: 1)")
+    var concurrency = 1
+
+    @Flag(name: .shortAndLong, help: "Output as JSON")
+    var json = false
+
+    @OptionGroup var compactOption: CompactOption
+
+    @OptionGroup var idFormatOption: IDFormatOption
+
+    @OptionGroup var quietOption: QuietOption
+
+    func validate() throws {
+        if compactOption.compact && !json {
+            throw ValidationError("--compact requires --json")
+        }
+        if includeEmpty && emptyOnly {
+            throw ValidationError("--include-empty and --empty-only cannot be used together")
+        }
+        if containsId != nil && containsName != nil {
+            throw ValidationError("--contains-id and --contains-name cannot be used together")
+        }
+        if (containsId != nil || containsName != nil) && (includeEmpty || emptyOnly) {
+            throw ValidationError("--contains-id and --contains-name cannot be combined with --include-empty or --empty-only")
+        }
+        if concurrency < 1 {
+            throw ValidationError("--concurrency must be at least 1")
+        }
+    }
+
+    func run() throws {
+        let service = ContactsService()
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        // Resolve the contact first, so a typo fails before every group is read
+        var contact: CNContact?
+        if let containsId {
+            contact = try service.getContact(id: containsId)
+        } else if let containsName {
+            contact = try service.getUniqueContact(name: containsName)
+        }
+        if (containsId != nil || containsName != nil) && contact == nil {
+            throw ContactsError.contactNotFound
+        }
+
+        let groups = try service.listGroups()
+        let members = try Parallel.map(groups, concurrency: concurrency) { try memberIDs(of: $0, service: service) }
+        let counted = zip(groups, members).map { (group: $0, count: $1?.count) }
+
+        // Groups that couldn't be counted are not known to be empty, so they stay visible
+        let shown: [(group: CNGroup, count: Int?)]
+        if let contact {
+            shown = zip(counted, members).filter { $1?.contains(contact.identifier) == true }.map(\.0)
+        } else if emptyOnly {
+            shown = counted.filter { $0.count == 0 }
+        } else if includeEmpty {
+            shown = counted
+        } else {
+            shown = counted.filter { $0.count != 0 }
+        }
+
+        if json {
+            printJSON(shown)
+        } else if quietOption.quiet {
+            // No table in --quiet mode
+        } else if let contact, shown.isEmpty {
+            print("\(contact.displayName.isEmpty ? idFormatOption.displayID(contact.identifier) : contact.displayName) is not in any group")
+        } else {
+            let hiddenEmpty = includeEmpty || emptyOnly || contact != nil ? 0 : counted.count - shown.count
+            printTable(shown, hiddenEmpty: hiddenEmpty)
+        }
+    }
+
+    /// Identifiers of the members, retrying once when the group can't be read. Returns nil
+    /// if it still fails, unless --strict-counts is set.
+    private func memberIDs(of group: CNGroup, service: ContactsService) throws -> Set<String>? {
+        do {
+            return try service.memberIDs(of: group)
+        } catch {
+            do {
+                return try service.memberIDs(of: group)
+            } catch {
+                if strictCounts {
+                    throw error
+                }
+                FileHandle.standardError.write(Data("Warning: could not count members of '\(group.name)': \(error.localizedDescription)\n".utf8))
+                return nil
+            }
+        }
+    }
+
+    private func printTable(_ groups: [(group: CNGroup, count: Int?)], hiddenEmpty: Int) {
+        if groups.isEmpty {
+            print(hiddenEmpty > 0 ? "No groups with members (\(hiddenEmpty) empty hidden; use --include-empty)" : "No groups found")
+            return
+        }
+
+        // Calculate column width
+        let nameWidth = max(5, groups.map { $0.group.name.count }.max() ?? 20)
+
+        // Header
+        print("\("GROUP".padding(toLength: nameWidth, withPad: " ", startingAt: 0))  MEMBERS")
+
+        // Rows
+        for (group, count) in groups {
+            print("\(group.name.padding(toLength: nameWidth, withPad: " ", startingAt: 0))  \(count.map(String.init) ?? "?")")
+        }
+
+        var footer = "\nTotal: \(groups.count) group(s)"
+        if hiddenEmpty > 0 {
+            footer += " (\(hiddenEmpty) empty hidden; use --include-empty)"
+        }
+        print(footer)
+    }
+
+    private func printJSON(_ groups: [(group: CNGroup, count: Int?)]) {
+        let data = groups.map { group, count -> [String: Any] in
+            [
+                "id": group.identifier,
+                "name": group.name,
+                "memberCount": count.map { $0 as Any } ?? NSNull(),
+            ]
+        }
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: compactOption.writingOptions()),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+}
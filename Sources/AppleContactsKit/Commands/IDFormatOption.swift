@@ -0,0 +1,14 @@
+import ArgumentParser
+import Foundation
+
+/// `--id-format` for every command that prints contact IDs. Part of `GlobalOptions`, and
+/// used on its own by commands that don't take the other global options.
+struct IDFormatOption: ParsableArguments {
+    @Option(name: .long, help: "How to print contact IDs: full (ABC123...:ABPerson) or uuid (without the suffix)")
+    var idFormat: IDFormat = .full
+
+    /// A contact ID as printed with --id-format
+    func displayID(_ id: String) -> String {
+        idFormat.apply(id)
+    }
+}
@@ -0,0 +1,259 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct Import: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "Import contacts from a vCard file",
+        discussion: """
+            Add the contacts in a .vcf file to Contacts. Use - to read the cards
+            from stdin. A file may hold any number of cards.
+
+            An imported card conflicts with an existing contact when they share
+            an email address or phone number (compared after normalization), or,
+            for cards with neither, the same full name. --on-conflict decides
+            what happens then:
+              skip       leave the existing contact alone (default)
+              update     merge the card's fields into the existing contact
+              duplicate  add the card as a new contact anyway
+
+            Cards are also compared with the cards imported before them: a second
+            card for the same person conflicts with the first one, and two cards
+            updating the same contact are both merged into it.
+
+            Every card is listed with the action taken. A card added as new while
+            a contact with the same name exists gets a warning on stderr, since it
+            may be the same person with different details.
+
+            Normally the import stops at the first card that can't be saved. With
+            --report, failed cards are recorded and the rest are still imported;
+            --retry-failed re-attempts only the cards a report lists as failed.
+
+            Examples:
+              apple-contacts import contacts.vcf
+              apple-contacts import contacts.vcf --on-conflict update
+              apple-contacts export --group Family | apple-contacts import - --on-conflict update
+              apple-contacts import contacts.vcf --on-conflict duplicate --json
+              apple-contacts import contacts.vcf --report import-report.json
+              apple-contacts import --retry-failed import-report.json --report retry.json
+            """
+    )
+
+    enum ConflictStrategy: String, ExpressibleByArgument, CaseIterable {
+        case skip
+        case update
+        case duplicate
+    }
+
+    @Argument(help: "vCard file to import, or - for stdin (default with --retry-failed: the file the report was written for)")
+    var file: String?
+
+    @Option(name: .long, help: "What to do when a card matches an existing contact (skip, update, duplicate)")
+    var onConflict: ConflictStrategy = .skip
+
+    @Option(name: .long, help: "Write each card's outcome (action, success, error) to this JSON file and continue past failures")
+    var report: String?
+
+    @Option(name: .long, help: "Re-attempt only the cards that failed in this --report file")
+    var retryFailed: String?
+
+    @Flag(name: .shortAndLong, help: "Output as JSON")
+    var json = false
+
+    @OptionGroup var options: WriteOptions
+
+    func validate() throws {
+        if file == nil && retryFailed == nil {
+            throw ValidationError("Please provide a vCard file to import")
+        }
+    }
+
+    /// What happened to one imported card
+    private struct Outcome {
+        enum Action: String {
+            case added
+            case skipped
+            case updated
+            case duplicated
+        }
+
+        let name: String
+        let action: Action
+        /// ID of the existing contact the card matched, if any
+        let matchedID: String?
+        let reason: String?
+        /// ID of an existing contact with the same name, for cards added as new
+        var sameNameID: String?
+        /// Why the card couldn't be saved, when it failed
+        var error: String?
+    }
+
+    func run() throws {
+        let service = ContactsService()
+        let log = options.logger(command: "import")
+        // --on-conflict update saves fetched contacts, which must keep their stored values
+        service.useStoredValues()
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        // Cards to re-attempt, by their position in the file
+        var retrying: Set<Int>?
+        var path = file
+        if let retryFailed {
+            let previous = try OperationReport.load(retryFailed, command: "import")
+            path = file ?? previous.input
+            retrying = Set(previous.failed.map(\.index))
+            if previous.failed.isEmpty {
+                FileHandle.standardError.write(Data("No failed cards in \(retryFailed)\n".utf8))
+                return
+            }
+        }
+        guard let path else {
+            throw ValidationError("The report doesn't name its vCard file; pass the file as well")
+        }
+
+        let fromStdin = path == "-"
+        let data = fromStdin ? FileHandle.standardInput.readDataToEndOfFile() : try Data(contentsOf: URL(fileURLWithPath: path))
+        guard let imported = try? CNContactVCardSerialization.contacts(with: data), !imported.isEmpty else {
+            throw ContactsError.invalidVCard(fromStdin ? "stdin" : path)
+        }
+
+        let existing = try service.fetchAll(keysToFetch: ContactsService.fullKeys)
+        var matcher = ImportMatcher(existing: existing, region: options.resolvedPhoneRegion)
+
+        // With a report, a card that fails is recorded and the rest are still imported
+        let keepGoing = report != nil || retryFailed != nil
+        var operations = OperationReport(command: "import", input: fromStdin ? nil : URL(fileURLWithPath: path).path)
+
+        // Saved contacts go back into the matcher, re-read with the keys it needs, so a later
+        // card that repeats this one matches it and a second update merges into the first
+        func remember(_ saved: CNContact) {
+            let stored = try? service.getContacts(ids: [saved.identifier], keysToFetch: ContactsService.fullKeys).first
+            matcher.insert(stored ?? saved)
+        }
+
+        var outcomes: [Outcome] = []
+        for (index, card) in imported.enumerated() where retrying?.contains(index) ?? true {
+            let name = card.displayName.isEmpty ? "(no name)" : card.displayName
+            let match = matcher.match(card)
+
+            let action: Outcome.Action
+            switch (match, onConflict) {
+            case (nil, _): action = .added
+            case (_, .skip): action = .skipped
+            case (_, .update): action = .updated
+            case (_, .duplicate): action = .duplicated
+            }
+            var outcome = Outcome(
+                name: name,
+                action: action,
+                matchedID: match.map { options.displayID($0.contact.identifier) },
+                reason: match?.reason
+            )
+            if action == .added, let namesake = matcher.sameName(card) {
+                outcome.sameNameID = options.displayID(namesake.identifier)
+                log.warning("\(name) has the same name as an existing contact; adding it as a new contact", ["id": options.displayID(namesake.identifier)])
+            }
+
+            do {
+                switch action {
+                case .added, .duplicated:
+                    let added = card.mutableCopy() as! CNMutableContact
+                    try service.addContact(added)
+                    remember(added)
+                case .updated:
+                    if let match {
+                        let merged = MergePreview.preview(into: match.contact, from: card).contact
+                        try service.updateContact(merged)
+                        remember(merged)
+                    }
+                case .skipped:
+                    break
+                }
+                operations.recordSuccess(index: index, id: match?.contact.identifier, name: name, action: action.rawValue)
+            } catch {
+                guard keepGoing else {
+                    throw error
+                }
+                operations.recordFailure(index: index, id: match?.contact.identifier, name: name, action: action.rawValue, error: error)
+                outcome.error = operations.failed.last?.error
+            }
+            outcomes.append(outcome)
+        }
+
+        if let report {
+            try operations.save(to: report)
+        }
+
+        if json {
+            printJSON(outcomes)
+        } else if !options.quiet {
+            printTable(outcomes)
+        }
+
+        if !operations.failed.isEmpty {
+            throw ExitCode.failure
+        }
+    }
+
+    private func printTable(_ outcomes: [Outcome]) {
+        let nameWidth = max(4, outcomes.map { $0.name.count }.max() ?? 20)
+
+        print("\("NAME".padding(toLength: nameWidth, withPad: " ", startingAt: 0))  ACTION      MATCHED")
+        for outcome in outcomes {
+            let name = outcome.name.padding(toLength: nameWidth, withPad: " ", startingAt: 0)
+            let action = (outcome.error == nil ? outcome.action.rawValue : "failed").padding(toLength: 10, withPad: " ", startingAt: 0)
+            var matched = "-"
+            if let error = outcome.error {
+                matched = error
+            } else if let matchedID = outcome.matchedID, let reason = outcome.reason {
+                matched = "\(matchedID) (\(reason))"
+            } else if let sameNameID = outcome.sameNameID {
+                matched = "\(sameNameID) (same name only)"
+            }
+            print("\(name)  \(action)  \(matched)")
+        }
+
+        let succeeded = outcomes.filter { $0.error == nil }
+        let counts = Dictionary(grouping: succeeded, by: \.action).mapValues(\.count)
+        var summary = [Outcome.Action.added, .updated, .duplicated, .skipped]
+            .map { "\(counts[$0, default: 0]) \($0.rawValue)" }
+            .joined(separator: ", ")
+        if succeeded.count < outcomes.count {
+            summary += ", \(outcomes.count - succeeded.count) failed"
+        }
+        print("\nImported \(outcomes.count) card(s): \(summary)")
+    }
+
+    private func printJSON(_ outcomes: [Outcome]) {
+        let data = outcomes.map { outcome -> [String: Any] in
+            var entry: [String: Any] = [
+                "name": outcome.name,
+                "action": outcome.action.rawValue,
+            ]
+            if let matchedID = outcome.matchedID {
+                entry["matchedId"] = matchedID
+            }
+            if let reason = outcome.reason {
+                entry["matchedBy"] = reason
+            }
+            if let sameNameID = outcome.sameNameID {
+                entry["sameNameId"] = sameNameID
+            }
+            if let error = outcome.error {
+                entry["error"] = error
+            }
+            return entry
+        }
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: .prettyPrinted),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+}
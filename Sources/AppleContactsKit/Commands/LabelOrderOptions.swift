@@ -0,0 +1,27 @@
+import ArgumentParser
+import Foundation
+
+/// Options controlling the order of a contact's phones and emails
+struct LabelOrderOptions: ParsableArguments {
+    @Flag(name: .long, help: "List the preferred phone/email first, by label preference")
+    var primaryFirst = false
+
+    @Option(name: .long, help: "Comma-separated label preference for --primary-first (e.g. work,mobile,home)")
+    var labelOrder: String?
+
+    /// Order to apply, or nil when --primary-first isn't set.
+    /// Falls back to labelOrder in the config file, then the built-in default.
+    var resolvedOrder: [String]? {
+        guard primaryFirst else { return nil }
+        if let labelOrder {
+            return labelOrder.split(separator: ",").map { $0.trimmingCharacters(in: .whitespaces) }
+        }
+        return (try? Config.load())?.labelOrder ?? LabelOrder.defaultOrder
+    }
+
+    func validate() throws {
+        if labelOrder != nil && !primaryFirst {
+            throw ValidationError("--label-order requires --primary-first")
+        }
+    }
+}
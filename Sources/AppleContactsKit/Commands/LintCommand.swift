@@ -0,0 +1,112 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct Lint: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "Check contacts for data-quality problems",
+        discussion: """
+            Scan all contacts for common data problems such as malformed
+            email addresses or misspelled email domains (e.g. gmial.com).
+            Problems with a known correction are reported as fixable, along
+            with the suggested value.
+
+            Exits with a non-zero status when problems are found.
+
+            Examples:
+              apple-contacts lint
+              apple-contacts lint --fixable-only
+              apple-contacts lint --json
+            """
+    )
+
+    @Flag(name: .long, help: "Only report problems with a suggested fix")
+    var fixableOnly = false
+
+    @Flag(name: .shortAndLong, help: "Output as JSON")
+    var json = false
+
+    @OptionGroup var idFormatOption: IDFormatOption
+
+    @OptionGroup var quietOption: QuietOption
+
+    func run() throws {
+        let service = ContactsService()
+        // Lint what is stored, not the trimmed values other commands show
+        service.useStoredValues()
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        let contacts = try service.fetchAll(keysToFetch: Linter.requiredKeys)
+        var issues = contacts.flatMap { Linter.issues(for: $0) }
+
+        if fixableOnly {
+            issues = issues.filter(\.isFixable)
+        }
+
+        if json {
+            printJSON(issues)
+        } else if !quietOption.quiet {
+            printTable(issues)
+        }
+
+        if !issues.isEmpty {
+            throw ExitCode.failure
+        }
+    }
+
+    private func printTable(_ issues: [LintIssue]) {
+        if issues.isEmpty {
+            print("No problems found")
+            return
+        }
+
+        // Calculate column widths
+        let nameWidth = max(4, min(30, issues.map { $0.contactName.count }.max() ?? 20))
+        let fieldWidth = max(5, issues.map { $0.field.count }.max() ?? 5)
+
+        // Header
+        print("\("NAME".padding(toLength: nameWidth, withPad: " ", startingAt: 0))  \("FIELD".padding(toLength: fieldWidth, withPad: " ", startingAt: 0))  PROBLEM")
+
+        // Rows
+        for issue in issues {
+            let name = String(issue.contactName.prefix(nameWidth)).padding(toLength: nameWidth, withPad: " ", startingAt: 0)
+            let field = issue.field.padding(toLength: fieldWidth, withPad: " ", startingAt: 0)
+            var line = "\(name)  \(field)  \(issue.value): \(issue.message)"
+            if let suggestion = issue.suggestion {
+                line += " (fix: \(suggestion))"
+            }
+            print(line)
+        }
+
+        let fixable = issues.filter(\.isFixable).count
+        print("\nFound \(issues.count) problem(s), \(fixable) fixable")
+    }
+
+    private func printJSON(_ issues: [LintIssue]) {
+        let data = issues.map { issue -> [String: Any] in
+            var entry: [String: Any] = [
+                "id": idFormatOption.displayID(issue.contactID),
+                "name": issue.contactName,
+                "field": issue.field,
+                "value": issue.value,
+                "message": issue.message,
+                "fixable": issue.isFixable,
+            ]
+            if let suggestion = issue.suggestion {
+                entry["suggestion"] = suggestion
+            }
+            return entry
+        }
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: .prettyPrinted),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+}
@@ -0,0 +1,682 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct List: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "List all contacts",
+        discussion: """
+            List contacts, optionally filtered by group.
+
+            Examples:
+              apple-contacts list
+              apple-contacts list --limit 10
+              apple-contacts list --group "Work"
+              apple-contacts list --no-note
+              apple-contacts list --after-id "ABC123:ABPerson" --limit 500
+              apple-contacts list --fields name,age,emailDomain,groupCount
+              apple-contacts list --fields-file columns.txt
+              apple-contacts list --template-file contact.txt
+              apple-contacts list --group Team --list-template-file roster.tmpl > roster.html
+              apple-contacts list --with-groups
+              apple-contacts list --show-source
+              apple-contacts list --department Engineering --fields name,department,jobTitle
+              apple-contacts list --not-in-group Family --not-in-group Work
+              apple-contacts list --exclude-source "On My Mac"
+              apple-contacts list --max-phones 0 --max-emails 0
+              apple-contacts list --filter 'org == "Acme" && len(phones) > 0'
+              apple-contacts list --fields name,email --jsonpath '$[*].email'
+              apple-contacts list --summary | grep Acme
+              apple-contacts list --group Team --format csv --output team.csv
+              apple-contacts list --random 5 --seed 42
+              apple-contacts list --newest 10
+              apple-contacts list --oldest 5 --json
+              apple-contacts list --max-fields 1
+              apple-contacts list --min-fields 3 --sort completeness
+              apple-contacts list --snapshot
+              apple-contacts list --changes-since-snapshot
+              apple-contacts list --json --progress-to fd3 3>progress.jsonl
+            """
+    )
+
+    enum SortOrder: String, ExpressibleByArgument, CaseIterable {
+        case completeness
+    }
+
+    @Option(name: .long, help: "Filter by group name")
+    var group: String?
+
+    @Option(name: .long, help: "Leave out members of this group (repeat to exclude several)")
+    var notInGroup: [String] = []
+
+    @Option(name: .long, help: "Leave out contacts stored in this account, e.g. \"On My Mac\" (repeat to exclude several)")
+    var excludeSource: [String] = []
+
+    @Option(name: .long, help: "Only contacts whose department contains this text")
+    var department: String?
+
+    @Option(name: .long, help: "Only contacts in exactly this department (case-insensitive)")
+    var departmentExact: String?
+
+    @Option(
+        name: .long,
+        help: ArgumentHelp(
+            "Only contacts matching this expression, e.g. 'org == \"Acme\" && len(phones) > 0'",
+            discussion: "See the README for the fields (name, org, age, phones, groups, ...), operators and functions (len, contains, cleanLabel, ...)."
+        )
+    )
+    var filter: String?
+
+    @Option(name: .shortAndLong, help: "Limit number of results")
+    var limit: Int?
+
+    @Option(name: .long, help: "Stable pagination: return contacts with an ID after this one, sorted by ID")
+    var afterId: String?
+
+    @Option(name: .long, help: "Return a random sample of this many contacts")
+    var random: Int?
+
+    @Option(name: .long, help: "Seed for --random, for a reproducible sample")
+    var seed: UInt64?
+
+    @Option(name: .long, help: "The N most recently added contacts, newest first")
+    var newest: Int?
+
+    @Option(name: .long, help: "The N earliest added contacts, oldest first")
+    var oldest: Int?
+
+    @Flag(name: .long, help: "Only contacts with a non-empty note")
+    var hasNote = false
+
+    @Flag(name: .long, help: "Only contacts without a note")
+    var noNote = false
+
+    @Option(name: .long, help: "Only contacts with at least this many filled-in values (name, org, birthday, note, each phone and email)")
+    var minFields: Int?
+
+    @Option(name: .long, help: "Only contacts with at most this many filled-in values")
+    var maxFields: Int?
+
+    @Option(name: .long, help: "Sort order (completeness: most filled-in first)")
+    var sort: SortOrder?
+
+    @Option(name: .long, help: "Comma-separated columns to show, including computed ones (age, emailDomain, groupCount)")
+    var fields: String?
+
+    @Option(name: .long, help: "File listing the columns, one per line (# for comments). --fields takes precedence")
+    var fieldsFile: String?
+
+    @Option(name: .long, help: "Print each contact with a template file using {{field}} placeholders (--fields names)")
+    var templateFile: String?
+
+    @Option(name: .long, help: "Print the whole list with a template file: header, {{range}}...{{end}} per contact, footer")
+    var listTemplateFile: String?
+
+    @Flag(name: .long, help: "Save a snapshot of every contact's fields for --changes-since-snapshot")
+    var snapshot = false
+
+    @Flag(name: .long, help: "Report contacts added, removed or modified since the last --snapshot")
+    var changesSinceSnapshot = false
+
+    @Flag(name: .long, help: "Add a column with the names of each contact's groups")
+    var withGroups = false
+
+    @Flag(name: .long, help: "Add a column with the account each contact is stored in, e.g. iCloud or On My Mac")
+    var showSource = false
+
+    @Flag(name: .long, help: "One line per contact: name, organization, primary phone and email")
+    var summary = false
+
+    @Flag(name: .shortAndLong, help: "Output as JSON")
+    var json = false
+
+    @OptionGroup var compactOption: CompactOption
+
+    @OptionGroup var csvOutput: CSVOutputOptions
+
+    @OptionGroup var valueCounts: ValueCountOptions
+
+    @OptionGroup var progressOption: ProgressOption
+
+    @OptionGroup var globals: GlobalOptions
+
+    /// Whether results are filtered after fetching, so the limit can't be applied while fetching
+    private var filtersContacts: Bool {
+        hasNote || noNote || filter != nil || !notInGroup.isEmpty || !excludeSource.isEmpty || valueCounts.isActive || department != nil || departmentExact != nil || afterId != nil || random != nil || newest != nil || oldest != nil || usesCompleteness
+    }
+
+    /// Whether completeness scores are needed for filtering or sorting
+    private var usesCompleteness: Bool {
+        minFields != nil || maxFields != nil || sort == .completeness
+    }
+
+    /// Columns from --fields, else --fields-file, or nil for the default table
+    private func resolveFields() throws -> [ContactField]? {
+        if let fields {
+            return try ContactField.parse(fields)
+        }
+        return try fieldsFile.map(ContactField.parseFile)
+    }
+
+    /// Template from --template-file or --list-template-file, if given
+    private func resolveTemplate() throws -> ListTemplate? {
+        if let templateFile {
+            return try ListTemplate(row: String(contentsOfFile: templateFile, encoding: .utf8))
+        }
+        if let listTemplateFile {
+            return try ListTemplate(parsing: String(contentsOfFile: listTemplateFile, encoding: .utf8))
+        }
+        return nil
+    }
+
+    func validate() throws {
+        if compactOption.compact && !json {
+            throw ValidationError("--compact requires --json")
+        }
+        if hasNote && noNote {
+            throw ValidationError("--has-note and --no-note cannot be used together")
+        }
+        if department != nil && departmentExact != nil {
+            throw ValidationError("--department and --department-exact cannot be used together")
+        }
+        _ = try resolveFields()
+        // Fail on expression errors before fetching any contacts
+        if let filter {
+            _ = try FilterExpression(parsing: filter)
+        }
+        if templateFile != nil || listTemplateFile != nil {
+            if templateFile != nil && listTemplateFile != nil {
+                throw ValidationError("--template-file and --list-template-file cannot be used together")
+            }
+            if json || summary || withGroups || showSource || fields != nil || fieldsFile != nil || globals.formatCommand != nil || globals.jsonpath != nil {
+                throw ValidationError("Templates cannot be combined with --json, --summary, --with-groups, --show-source, --fields, --format-command or --jsonpath")
+            }
+            // Fail on template errors before fetching any contacts
+            _ = try resolveTemplate()
+        }
+        if let random, random < 1 {
+            throw ValidationError("--random must be at least 1")
+        }
+        if seed != nil && random == nil {
+            throw ValidationError("--seed requires --random")
+        }
+        if random != nil && afterId != nil {
+            throw ValidationError("--random cannot be combined with --after-id")
+        }
+        if snapshot && changesSinceSnapshot {
+            throw ValidationError("--snapshot and --changes-since-snapshot cannot be used together")
+        }
+        if let minFields, let maxFields, minFields > maxFields {
+            throw ValidationError("--min-fields cannot be greater than --max-fields")
+        }
+        if sort != nil && (afterId != nil || random != nil) {
+            throw ValidationError("--sort cannot be combined with --after-id or --random")
+        }
+        if newest != nil || oldest != nil {
+            if newest != nil && oldest != nil {
+                throw ValidationError("--newest and --oldest cannot be used together")
+            }
+            if (newest ?? oldest ?? 0) < 1 {
+                throw ValidationError("--newest and --oldest must be at least 1")
+            }
+            if sort != nil || afterId != nil || random != nil {
+                throw ValidationError("--newest and --oldest cannot be combined with --sort, --after-id or --random")
+            }
+        }
+        if csvOutput.isCSV && (json || summary || withGroups || showSource || fields != nil || fieldsFile != nil || templateFile != nil || listTemplateFile != nil || globals.formatCommand != nil || globals.jsonpath != nil) {
+            throw ValidationError("--format csv cannot be combined with --json, --summary, --with-groups, --show-source, --fields, templates, --format-command or --jsonpath")
+        }
+        if summary && (json || withGroups || showSource || fields != nil || fieldsFile != nil) {
+            throw ValidationError("--summary cannot be combined with --json, --with-groups, --show-source or --fields")
+        }
+    }
+
+    func run() throws {
+        let service = globals.makeService()
+        service.progress = try progressOption.makeSink()
+        let log = globals.logger(command: "list")
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        if snapshot || changesSinceSnapshot {
+            try runSnapshot(service: service)
+            log.finish()
+            return
+        }
+
+        let template = try resolveTemplate()
+        var selectedFields = try template?.fields ?? resolveFields()
+        if withGroups, let fields = selectedFields, !fields.contains(where: { $0.name == "groups" }) {
+            selectedFields = fields + (try ContactField.parse("groups"))
+        }
+        if showSource, let fields = selectedFields, !fields.contains(where: { $0.name == "source" }) {
+            selectedFields = fields + (try ContactField.parse("source"))
+        }
+        var extraKeys = selectedFields?.flatMap(\.keys) ?? []
+        if usesCompleteness {
+            extraKeys += CNContact.completenessKeys
+        }
+        if summary {
+            extraKeys += CNContact.summaryKeys
+        }
+        if csvOutput.isCSV {
+            extraKeys += CSVOutputOptions.keys
+        }
+        let expression = try filter.map(FilterExpression.init(parsing:))
+        if expression != nil {
+            extraKeys += FilterExpression.requiredKeys
+        }
+
+        var contacts: [CNContact]
+
+        if let groupName = group {
+            guard let group = try service.getGroup(name: groupName) else {
+                throw ContactsError.groupNotFound
+            }
+            contacts = try service.listContactsInGroup(group, extraKeys: extraKeys)
+        } else if filtersContacts {
+            // Filter before limiting so the limit applies to matching contacts
+            contacts = try service.listContacts(extraKeys: extraKeys)
+        } else {
+            contacts = try service.listContacts(limit: limit, extraKeys: extraKeys)
+        }
+
+        log.debug("fetched contacts", ["count": contacts.count, "elapsed_ms": log.elapsedMilliseconds])
+
+        if let department = departmentExact ?? department {
+            let matches = Set(try service.searchByDepartment(department, exact: departmentExact != nil).map(\.identifier))
+            contacts = contacts.filter { matches.contains($0.identifier) }
+        }
+
+        if !notInGroup.isEmpty {
+            let excluded = try service.memberIDs(ofGroupsNamed: notInGroup)
+            contacts = contacts.filter { !excluded.contains($0.identifier) }
+        }
+
+        if !excludeSource.isEmpty {
+            let excluded = try service.memberIDs(ofSourcesNamed: excludeSource)
+            contacts = contacts.filter { !excluded.contains($0.identifier) }
+        }
+
+        if valueCounts.isActive {
+            let counts = try service.valueCounts()
+            contacts = contacts.filter { counts[$0.identifier].map(valueCounts.matches) ?? false }
+        }
+
+        if hasNote || noNote {
+            let noteMatches = Set(try service.searchByNote(present: hasNote).map(\.identifier))
+            contacts = contacts.filter { noteMatches.contains($0.identifier) }
+        }
+
+        if let expression {
+            let groupNames = expression.usesGroups ? try service.groupNames() : [:]
+            contacts = try contacts.filter { try expression.matches($0, groups: groupNames[$0.identifier] ?? []) }
+        }
+
+        if usesCompleteness {
+            // Notes only count when the binary is entitled to read them
+            var notedIDs = Set<String>()
+            if let noted = try? service.searchByNote(present: true) {
+                notedIDs = Set(noted.map(\.identifier))
+            } else {
+                log.warning("notes are not readable; they don't count toward completeness")
+            }
+
+            var scores: [String: Int] = [:]
+            for contact in contacts {
+                scores[contact.identifier] = contact.completenessScore(hasNote: notedIDs.contains(contact.identifier))
+            }
+
+            contacts = contacts.filter { contact in
+                let score = scores[contact.identifier, default: 0]
+                return score >= (minFields ?? 0) && score <= (maxFields ?? .max)
+            }
+            if sort == .completeness {
+                contacts.sort { scores[$0.identifier, default: 0] > scores[$1.identifier, default: 0] }
+            }
+        }
+
+        // Order by when contacts were added; those without a creation date are left out
+        var created: [String: Date]?
+        if let count = newest ?? oldest {
+            let dates = try ModificationDates.created()
+            let dated = contacts.compactMap { contact in dates[contact.identifier].map { (contact, $0) } }
+            let ordered = dated.sorted { newest != nil ? $0.1 > $1.1 : $0.1 < $1.1 }
+            contacts = ordered.prefix(count).map(\.0)
+            created = dates
+        }
+
+        // Sort by ID and skip past the cursor so pages stay stable when the book changes
+        if let afterId {
+            contacts = Self.page(contacts, after: afterId)
+        }
+
+        // Shuffle the whole list before sampling so every contact is equally likely
+        if let random {
+            if let seed {
+                var generator = SeededGenerator(seed: seed)
+                contacts.shuffle(using: &generator)
+            } else {
+                contacts.shuffle()
+            }
+            contacts = Array(contacts.prefix(random))
+        }
+
+        // Apply limit if contacts were filtered (listContacts already handles limit)
+        if group != nil || filtersContacts, let limit = limit, contacts.count > limit {
+            contacts = Array(contacts.prefix(limit))
+        }
+
+        let formatter = globals.externalFormatter(log: log)
+        var formatFailures = 0
+
+        if let selectedFields {
+            // Only look up group membership and sources when a selected column needs them
+            var context = FieldContext(redaction: globals.redaction, idFormat: globals.idFormat, nameLocale: globals.nameLocale)
+            if selectedFields.contains(where: \.needsGroups) {
+                context.groupNames = try service.groupNames()
+                context.groupCounts = context.groupNames.mapValues(\.count)
+            }
+            if selectedFields.contains(where: \.needsSources) {
+                context.sourceNames = try service.sourceNames()
+            }
+
+            if let template {
+                print(template.render(contacts, context: context), terminator: "")
+            } else if let formatter {
+                formatFailures = formatter.run(fieldsJSONEntries(contacts, fields: selectedFields, context: context))
+            } else if let path = globals.jsonPath {
+                try globals.printJSONPath(path, in: fieldsJSONEntries(contacts, fields: selectedFields, context: context))
+            } else if json {
+                printFieldsJSON(contacts, fields: selectedFields, context: context)
+            } else if !globals.quiet {
+                printFieldsTable(contacts, fields: selectedFields, context: context)
+            }
+        } else {
+            let groupNames = withGroups ? try service.groupNames() : nil
+            let sourceNames = showSource ? try service.sourceNames() : nil
+            if let formatter {
+                formatFailures = formatter.run(jsonEntries(contacts, groupNames: groupNames, sourceNames: sourceNames, created: created))
+            } else if let path = globals.jsonPath {
+                try globals.printJSONPath(path, in: jsonEntries(contacts, groupNames: groupNames, sourceNames: sourceNames, created: created))
+            } else if json {
+                printJSON(contacts, groupNames: groupNames, sourceNames: sourceNames, created: created)
+            } else if csvOutput.isCSV {
+                try csvOutput.write(contacts, redaction: globals.redaction, displayID: globals.displayID, nameLocale: globals.nameLocale)
+            } else if globals.quiet {
+                // No table in --quiet mode
+            } else if summary {
+                printSummary(contacts)
+            } else {
+                printTable(contacts, groupNames: groupNames, sourceNames: sourceNames, created: created)
+            }
+        }
+
+        // JSON carries the cursor itself; other machine-readable output gets it on stderr
+        if afterId != nil, !json, let last = contacts.last {
+            let hint = "Next page: --after-id \"\(last.identifier)\""
+            if csvOutput.isCSV || globals.quiet || formatter != nil || template != nil || globals.jsonpath != nil {
+                FileHandle.standardError.write(Data("\(hint)\n".utf8))
+            } else {
+                print(hint)
+            }
+        }
+
+        log.finish()
+
+        if formatFailures > 0 {
+            throw ExitCode.failure
+        }
+    }
+
+    /// Save a snapshot, or compare the current contacts against the saved one
+    private func runSnapshot(service: ContactsService) throws {
+        // Compare stored values, not whitespace-normalized ones
+        service.useStoredValues()
+        let current = Snapshot(contacts: try service.fetchAll(keysToFetch: ContactsService.fullKeys))
+
+        if snapshot {
+            try current.save()
+            if !globals.quiet {
+                print("Saved snapshot of \(current.fields.count) contact(s) to \(Snapshot.fileURL.path)")
+            }
+            return
+        }
+
+        let previous = try Snapshot.load()
+        let changes = current.changes(since: previous)
+
+        if json {
+            printChangesJSON(changes, since: previous.createdAt)
+        } else if !globals.quiet {
+            printChanges(changes, since: previous.createdAt)
+        }
+    }
+
+    private func printChanges(_ changes: Snapshot.Changes, since date: Date) {
+        let when = date.formatted(date: .abbreviated, time: .shortened)
+        if changes.isEmpty {
+            print("No changes since snapshot of \(when)")
+            return
+        }
+
+        print("Changes since snapshot of \(when):\n")
+        for contact in changes.added {
+            print("  + \(contact.name)  \(contact.id)")
+        }
+        for contact in changes.removed {
+            print("  - \(contact.name)  \(contact.id)")
+        }
+        for contact in changes.modified {
+            print("  ~ \(contact.name)  \(contact.id)  (\(contact.fields.joined(separator: ", ")))")
+        }
+
+        print("\n\(changes.added.count) added, \(changes.removed.count) removed, \(changes.modified.count) modified")
+    }
+
+    private func printChangesJSON(_ changes: Snapshot.Changes, since date: Date) {
+        let data: [String: Any] = [
+            "since": ISO8601DateFormatter().string(from: date),
+            "added": changes.added.map { ["id": $0.id, "name": $0.name] },
+            "removed": changes.removed.map { ["id": $0.id, "name": $0.name] },
+            "modified": changes.modified.map { ["id": $0.id, "name": $0.name, "fields": $0.fields] as [String: Any] },
+        ]
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: compactOption.writingOptions(.sortedKeys)),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+
+    private func printTable(
+        _ contacts: [CNContact],
+        groupNames: [String: [String]]? = nil,
+        sourceNames: [String: [String]]? = nil,
+        created: [String: Date]? = nil
+    ) {
+        if contacts.isEmpty {
+            print("No contacts found")
+            return
+        }
+
+        // Calculate column widths
+        let names = contacts.map { $0.fullName(locale: globals.nameLocale) }
+        let nameWidth = max(4, min(30, names.map(\.count).max() ?? 20))
+        let orgWidth = max(12, min(25, contacts.map { $0.organizationName.count }.max() ?? 15))
+        let groupsWidth = groupNames.map { names in
+            max(6, min(30, contacts.map { (names[$0.identifier] ?? []).joined(separator: ", ").count }.max() ?? 10))
+        }
+        let sourceWidth = sourceNames.map { names in
+            max(9, min(30, contacts.map { ContactsService.sourceLabel(names[$0.identifier]).count }.max() ?? 10))
+        }
+        let addedColumn = created.map { dates in
+            contacts.map { contact in dates[contact.identifier].map(globals.dateDisplay.date) ?? "-" }
+        }
+        let addedWidth = addedColumn.map { column in max(5, column.map(\.count).max() ?? 5) }
+
+        // Header
+        var header = "\("NAME".padding(toLength: nameWidth, withPad: " ", startingAt: 0))  \("ORGANIZATION".padding(toLength: orgWidth, withPad: " ", startingAt: 0))  "
+        if let groupsWidth {
+            header += "\("GROUPS".padding(toLength: groupsWidth, withPad: " ", startingAt: 0))  "
+        }
+        if let sourceWidth {
+            header += "\("SOURCE".padding(toLength: sourceWidth, withPad: " ", startingAt: 0))  "
+        }
+        if let addedWidth {
+            header += "\("ADDED".padding(toLength: addedWidth, withPad: " ", startingAt: 0))  "
+        }
+        print(header + "ID")
+
+        // Rows
+        for (index, contact) in contacts.enumerated() {
+            let name = String(names[index].prefix(nameWidth)).padding(toLength: nameWidth, withPad: " ", startingAt: 0)
+            let org = (contact.organizationName.isEmpty ? "-" : String(contact.organizationName.prefix(orgWidth)))
+                .padding(toLength: orgWidth, withPad: " ", startingAt: 0)
+
+            var row = "\(name)  \(org)  "
+            if let groupNames, let groupsWidth {
+                let groups = (groupNames[contact.identifier] ?? []).joined(separator: ", ")
+                row += "\((groups.isEmpty ? "-" : String(groups.prefix(groupsWidth))).padding(toLength: groupsWidth, withPad: " ", startingAt: 0))  "
+            }
+            if let sourceNames, let sourceWidth {
+                let source = ContactsService.sourceLabel(sourceNames[contact.identifier])
+                row += "\(String(source.prefix(sourceWidth)).padding(toLength: sourceWidth, withPad: " ", startingAt: 0))  "
+            }
+            if let addedColumn, let addedWidth {
+                row += "\(addedColumn[index].padding(toLength: addedWidth, withPad: " ", startingAt: 0))  "
+            }
+            print(row + globals.displayID(contact.identifier))
+        }
+
+        print("\nTotal: \(contacts.count) contact(s)")
+    }
+
+    private func printSummary(_ contacts: [CNContact]) {
+        let redaction = globals.redaction
+        for contact in contacts {
+            print(contact.summaryLine(redaction: redaction, nameLocale: globals.nameLocale))
+        }
+    }
+
+    private func printFieldsTable(_ contacts: [CNContact], fields: [ContactField], context: FieldContext) {
+        if contacts.isEmpty {
+            print("No contacts found")
+            return
+        }
+
+        let rows = contacts.map { contact in fields.map { $0.value(contact, context) } }
+
+        // Calculate column widths
+        let widths = fields.enumerated().map { i, field in
+            max(field.header.count, min(30, rows.map { $0[i].count }.max() ?? 0))
+        }
+
+        // Header
+        let header = fields.enumerated().map { i, field in
+            field.header.padding(toLength: widths[i], withPad: " ", startingAt: 0)
+        }
+        print(header.joined(separator: "  "))
+
+        // Rows
+        for row in rows {
+            let cells = row.enumerated().map { i, value in
+                String((value.isEmpty ? "-" : value).prefix(widths[i])).padding(toLength: widths[i], withPad: " ", startingAt: 0)
+            }
+            print(cells.joined(separator: "  "))
+        }
+
+        print("\nTotal: \(contacts.count) contact(s)")
+    }
+
+    private func fieldsJSONEntries(_ contacts: [CNContact], fields: [ContactField], context: FieldContext) -> [[String: Any]] {
+        contacts.map { contact -> [String: Any] in
+            var entry: [String: Any] = [:]
+            for field in fields {
+                entry[field.name] = field.value(contact, context)
+            }
+            return entry
+        }
+    }
+
+    /// Contacts sorted by ID that come after the `--after-id` cursor ("" for the first page).
+    /// IDs are compared without the `:ABPerson` suffix, so a cursor printed with
+    /// --id-format uuid continues where it left off.
+    static func page(_ contacts: [CNContact], after cursor: String) -> [CNContact] {
+        let cursor = IDFormat.shortenID(cursor)
+        return contacts
+            .map { (contact: $0, id: IDFormat.shortenID($0.identifier)) }
+            .sorted { $0.id < $1.id }
+            .filter { cursor.isEmpty || $0.id > cursor }
+            .map(\.contact)
+    }
+
+    /// --json output, wrapped as `{"contacts": [...], "nextAfterId": ...}` with --after-id
+    /// so callers get the cursor for the next page (null after the last page)
+    private func paged(_ entries: [[String: Any]], contacts: [CNContact]) -> Any {
+        guard afterId != nil else {
+            return entries
+        }
+        return [
+            "contacts": entries,
+            "nextAfterId": contacts.last.map { $0.identifier as Any } ?? NSNull(),
+        ]
+    }
+
+    private func printFieldsJSON(_ contacts: [CNContact], fields: [ContactField], context: FieldContext) {
+        let data = paged(fieldsJSONEntries(contacts, fields: fields, context: context), contacts: contacts)
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: compactOption.writingOptions()),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+
+    private func jsonEntries(
+        _ contacts: [CNContact],
+        groupNames: [String: [String]]? = nil,
+        sourceNames: [String: [String]]? = nil,
+        created: [String: Date]? = nil
+    ) -> [[String: Any]] {
+        contacts.map { contact -> [String: Any] in
+            var entry: [String: Any] = [
+                "id": globals.displayID(contact.identifier),
+                "name": contact.fullName(locale: globals.nameLocale),
+                "firstName": contact.givenName,
+                "lastName": contact.familyName,
+                "nickname": contact.nickname,
+                "organization": contact.organizationName,
+            ]
+            if let groupNames {
+                entry["groups"] = groupNames[contact.identifier] ?? []
+            }
+            if let sourceNames {
+                entry["source"] = ContactsService.sourceLabel(sourceNames[contact.identifier])
+            }
+            if let date = created?[contact.identifier] {
+                entry["created"] = ISO8601DateFormatter().string(from: date)
+            }
+            return entry
+        }
+    }
+
+    private func printJSON(
+        _ contacts: [CNContact],
+        groupNames: [String: [String]]? = nil,
+        sourceNames: [String: [String]]? = nil,
+        created: [String: Date]? = nil
+    ) {
+        let data = paged(jsonEntries(contacts, groupNames: groupNames, sourceNames: sourceNames, created: created), contacts: contacts)
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: compactOption.writingOptions()),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+}
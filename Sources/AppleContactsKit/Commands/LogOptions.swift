@@ -0,0 +1,16 @@
+import ArgumentParser
+import Foundation
+
+/// `--verbose` and `--log-json`, for diagnostics on stderr. Part of `GlobalOptions` and `WriteOptions`.
+struct LogOptions: ParsableArguments {
+    @Flag(name: .shortAndLong, help: "Print diagnostics and timing to stderr")
+    var verbose = false
+
+    @Flag(name: .long, help: "Write diagnostics to stderr as JSON lines (level, msg, command, duration)")
+    var logJson = false
+
+    /// Logger for a command, configured from --verbose and --log-json
+    func logger(command: String) -> Logger {
+        Logger(command: command, json: logJson, verbose: verbose)
+    }
+}
@@ -0,0 +1,142 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct Merge: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "Preview merging one contact into another",
+        discussion: """
+            Show what a contact would look like after merging another contact
+            into it, and which values come from which contact. Scalar fields keep
+            the target's value when it has one; phones, emails, addresses and other
+            multi-valued fields are combined without duplicates.
+
+            Contacts can be given by ID or name. Nothing is saved.
+
+            Examples:
+              apple-contacts merge --into "Erik Fisher" --from "Erik F" --preview
+              apple-contacts merge --into ABC123:ABPerson --from DEF456:ABPerson --preview --json
+            """
+    )
+
+    @Option(name: .long, help: "Contact to keep (ID or name)")
+    var into: String
+
+    @Option(name: .long, help: "Contact to merge in (ID or name)")
+    var from: String
+
+    @Flag(name: .long, help: "Show the merged result without changing anything")
+    var preview = false
+
+    @Flag(name: .shortAndLong, help: "Output as JSON")
+    var json = false
+
+    @OptionGroup var idFormatOption: IDFormatOption
+
+    @OptionGroup var quietOption: QuietOption
+
+    func validate() throws {
+        if !preview {
+            throw ValidationError("Only previews are supported; run with --preview")
+        }
+    }
+
+    func run() throws {
+        let service = ContactsService()
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        guard let target = try service.getContact(id: into) ?? service.getContact(name: into),
+              let source = try service.getContact(id: from) ?? service.getContact(name: from)
+        else {
+            throw ContactsError.contactNotFound
+        }
+        if target.identifier == source.identifier {
+            throw ValidationError("--into and --from refer to the same contact")
+        }
+
+        let (merged, notes) = MergePreview.preview(into: target, from: source)
+
+        if json {
+            printJSON(merged, notes: notes, source: source)
+        } else if !quietOption.quiet {
+            printPreview(merged, notes: notes, source: source)
+        }
+    }
+
+    private static let sections: [(field: String, title: String)] = [
+        ("phone", "PHONES"),
+        ("email", "EMAILS"),
+        ("address", "ADDRESSES"),
+        ("url", "URLS"),
+        ("social", "SOCIAL"),
+        ("relation", "RELATIONS"),
+    ]
+
+    private static let scalarLabels: [(field: String, label: String)] = [
+        ("firstName", "First Name"),
+        ("middleName", "Middle Name"),
+        ("lastName", "Last Name"),
+        ("nickname", "Nickname"),
+        ("organization", "Organization"),
+        ("department", "Department"),
+        ("jobTitle", "Job Title"),
+        ("birthday", "Birthday"),
+    ]
+
+    private func printPreview(_ merged: CNContact, notes: [MergeNote], source: CNContact) {
+        print("Preview of merging \"\(source.fullName)\" into \"\(merged.fullName)\" (nothing is saved)\n")
+
+        let kept = notes.filter { !$0.discarded }
+
+        for (field, label) in Self.scalarLabels {
+            for note in kept where note.field == field {
+                let heading = "\(label):".padding(toLength: 14, withPad: " ", startingAt: 0)
+                print("\(heading)\(note.value)  [\(note.source.rawValue)]")
+            }
+        }
+
+        for (field, title) in Self.sections {
+            let values = kept.filter { $0.field == field }
+            if values.isEmpty { continue }
+            print("\n\(title):")
+            for note in values {
+                print("  [\(note.source.rawValue.padding(toLength: 4, withPad: " ", startingAt: 0))] \(note.value)")
+            }
+        }
+
+        let discarded = notes.filter(\.discarded)
+        if !discarded.isEmpty {
+            print("\nDROPPED (target already has a value):")
+            for note in discarded {
+                print("  \(note.field.padding(toLength: 12, withPad: " ", startingAt: 0)) \(note.value)")
+            }
+        }
+
+        print("\nResult ID: \(idFormatOption.displayID(merged.identifier))")
+    }
+
+    private func printJSON(_ merged: CNContact, notes: [MergeNote], source: CNContact) {
+        func entries(_ notes: [MergeNote]) -> [[String: String]] {
+            notes.map { ["field": $0.field, "value": $0.value, "source": $0.source.rawValue] }
+        }
+
+        let data: [String: Any] = [
+            "id": idFormatOption.displayID(merged.identifier),
+            "name": merged.fullName,
+            "fromId": idFormatOption.displayID(source.identifier),
+            "values": entries(notes.filter { !$0.discarded }),
+            "discarded": entries(notes.filter(\.discarded)),
+        ]
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: [.prettyPrinted, .sortedKeys]),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+}
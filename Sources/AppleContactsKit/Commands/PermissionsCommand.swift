@@ -19,44 +19,66 @@ struct Permissions: ParsableCommand {
     @Flag(name: .long, help: "Show instructions to reset permissions")
     var reset: Bool = false
 
+    @OptionGroup var quietOption: QuietOption
+
     func run() throws {
         let status = CNContactStore.authorizationStatus(for: .contacts)
 
-        print("Current status: \(statusDescription(status))")
-        print("Executable: \(ProcessInfo.processInfo.arguments[0])")
-
-        if let parentProcess = getParentProcessName() {
-            print("Parent process: \(parentProcess)")
-        }
-
+        // --reset output is what was asked for, so --quiet leaves it alone
         if reset {
             printResetInstructions()
             return
         }
 
+        say("Current status: \(statusDescription(status))")
+        say("Executable: \(ProcessInfo.processInfo.arguments[0])")
+
+        if !quietOption.quiet, let parentProcess = getParentProcessName() {
+            print("Parent process: \(parentProcess)")
+        }
+
         switch status {
         case .notDetermined:
-            print("\nRequesting access...")
+            say("\nRequesting access...")
             requestAccessSync()
 
         case .authorized:
-            print("\nContacts access is granted. No action needed.")
+            say("\nContacts access is granted. No action needed.")
 
         case .denied:
-            print("\nAccess was denied. To reset and request again:")
-            printResetInstructions()
+            if quietOption.quiet {
+                FileHandle.standardError.write(Data("Contacts access was denied\n".utf8))
+            } else {
+                print("\nAccess was denied. To reset and request again:")
+                printResetInstructions()
+            }
             throw ExitCode.failure
 
         case .restricted:
-            print("\nAccess is restricted by system policy (parental controls, MDM, etc.)")
+            if quietOption.quiet {
+                FileHandle.standardError.write(Data("Contacts access is restricted by system policy\n".utf8))
+            } else {
+                print("\nAccess is restricted by system policy (parental controls, MDM, etc.)")
+            }
             throw ExitCode.failure
 
         @unknown default:
-            print("\nUnknown authorization status")
+            if quietOption.quiet {
+                FileHandle.standardError.write(Data("Unknown Contacts authorization status\n".utf8))
+            } else {
+                print("\nUnknown authorization status")
+            }
             throw ExitCode.failure
         }
     }
 
+    /// Print a status line unless --quiet
+    private func say(_ line: String) {
+        if !quietOption.quiet {
+            print(line)
+        }
+    }
+
     private func statusDescription(_ status: CNAuthorizationStatus) -> String {
         switch status {
         case .notDetermined: return "Not Determined (permission not yet requested)"
@@ -82,14 +104,14 @@ struct Permissions: ParsableCommand {
         semaphore.wait()
 
         if granted {
-            print("Access granted!")
+            say("Access granted!")
         } else if let error = accessError {
-            print("Access request failed: \(error.localizedDescription)")
+            FileHandle.standardError.write(Data("Access request failed: \(error.localizedDescription)\n".utf8))
         } else {
-            print("Access denied by user.")
-            print("\nTo grant access, go to:")
-            print("  System Settings > Privacy & Security > Contacts")
-            print("  and enable access for your terminal or Node.js application.")
+            say("Access denied by user.")
+            say("\nTo grant access, go to:")
+            say("  System Settings > Privacy & Security > Contacts")
+            say("  and enable access for your terminal or Node.js application.")
         }
     }
 
@@ -0,0 +1,31 @@
+import ArgumentParser
+import Foundation
+
+/// `--phone-region` for commands that normalize phone numbers. Part of `GlobalOptions`,
+/// and of `WriteOptions` for commands that add contacts.
+struct PhoneRegionOption: ParsableArguments {
+    @Option(
+        name: .long,
+        help: ArgumentHelp(
+            "Default region for phone numbers without a country code (e.g. NO, US)",
+            discussion: "Defaults to $APPLE_CONTACTS_PHONE_REGION, then phoneRegion in the config file, then the system region setting."
+        )
+    )
+    var phoneRegion: String?
+
+    /// Region used to normalize phone numbers: --phone-region, $APPLE_CONTACTS_PHONE_REGION,
+    /// the config file, then the system region
+    var resolvedPhoneRegion: String? {
+        phoneRegion?.uppercased()
+            ?? PhoneNumbers.environmentRegion
+            ?? (try? Config.load())?.phoneRegion?.uppercased()
+            ?? PhoneNumbers.systemRegion
+    }
+
+    func validate() throws {
+        if let phoneRegion, PhoneNumbers.callingCodes[phoneRegion.uppercased()] == nil {
+            let known = PhoneNumbers.callingCodes.keys.sorted().joined(separator: ", ")
+            throw ValidationError("Unknown phone region '\(phoneRegion)'. Known regions: \(known)")
+        }
+    }
+}
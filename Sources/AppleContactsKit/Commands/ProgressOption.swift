@@ -0,0 +1,26 @@
+import ArgumentParser
+import Foundation
+
+/// `--progress-to` for list and search: JSON progress events while contacts are fetched,
+/// for wrappers that show a progress bar. Export has its own, covering serialization.
+struct ProgressOption: ParsableArguments {
+    @Option(
+        name: .long,
+        help: ArgumentHelp(
+            "Write JSON progress events ({\"done\":120,\"total\":4000}) to a file or a file descriptor (fd3)",
+            discussion: "Reported while contacts are fetched. Each pass over the contacts starts again from 0."
+        )
+    )
+    var progressTo: String?
+
+    func validate() throws {
+        if let progressTo {
+            _ = try JSONProgressSink.parse(progressTo)
+        }
+    }
+
+    /// Sink for --progress-to, if given
+    func makeSink() throws -> ProgressSink? {
+        try progressTo.map { try JSONProgressSink(target: $0) }
+    }
+}
@@ -0,0 +1,223 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct Prune: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "Find and delete effectively empty contacts",
+        discussion: """
+            List contacts that hold nothing at all, typically junk entries left
+            behind by bad syncs. A contact counts as empty only when every one of
+            these is blank: first, middle and last name, phonetic names, nickname,
+            organization, department, job title, note, phones, emails, addresses,
+            URLs, social profiles, instant message addresses, related names,
+            birthday, other dates and photo.
+
+            Nothing is deleted unless --force is given.
+            Checking notes requires the Contacts notes entitlement.
+
+            With --report, each contact is deleted on its own and failures are
+            recorded instead of stopping the run; --retry-failed deletes only the
+            contacts a report lists as failed, if they are still empty.
+
+            With --confirm-each, every candidate is shown and deleted only when you
+            answer y; n skips it and q skips it and all the rest. Contacts you
+            confirmed before quitting are still deleted.
+
+            Examples:
+              apple-contacts prune
+              apple-contacts prune --force
+              apple-contacts prune --force --confirm-each
+              apple-contacts prune --force --report prune-report.json
+              apple-contacts prune --force --retry-failed prune-report.json
+            """
+    )
+
+    @Flag(name: .long, help: "List empty contacts without deleting (default)")
+    var dryRun = false
+
+    @Flag(name: .long, help: "Delete the empty contacts")
+    var force = false
+
+    @Flag(name: .long, help: "With --force, ask before deleting each contact (y/n/q)")
+    var confirmEach = false
+
+    @Option(name: .long, help: "With --force, write each deletion's outcome to this JSON file and continue past failures")
+    var report: String?
+
+    @Option(name: .long, help: "With --force, delete only the contacts that failed in this --report file")
+    var retryFailed: String?
+
+    @Flag(name: .shortAndLong, help: "Output as JSON")
+    var json = false
+
+    @OptionGroup var idFormatOption: IDFormatOption
+
+    @OptionGroup var quietOption: QuietOption
+
+    func validate() throws {
+        if dryRun && force {
+            throw ValidationError("--dry-run and --force cannot be used together")
+        }
+        if (report != nil || retryFailed != nil) && !force {
+            throw ValidationError("--report and --retry-failed require --force")
+        }
+        if confirmEach && !force {
+            throw ValidationError("--confirm-each requires --force")
+        }
+    }
+
+    func run() throws {
+        let service = ContactsService()
+        // Emptiness is judged on the stored values, not on trimmed ones
+        service.useStoredValues()
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        // Judging emptiness needs every field, including the note
+        let contacts: [CNContact]
+        do {
+            contacts = try service.fetchAll(keysToFetch: CNContact.emptinessKeys)
+        } catch {
+            throw ContactsError.notesUnavailable
+        }
+        var empty = contacts.filter(\.isEffectivelyEmpty)
+
+        if let retryFailed {
+            let failedIDs = Set(try OperationReport.load(retryFailed, command: "prune").failed.compactMap(\.id))
+            empty = empty.filter { failedIDs.contains($0.identifier) }
+        }
+
+        // IDs of contacts the user chose not to delete
+        var skipped = Set<String>()
+        if confirmEach && !empty.isEmpty {
+            skipped = try confirmDeletions(empty)
+        }
+        let toDelete = empty.filter { !skipped.contains($0.identifier) }
+
+        // Contact ID to the error that kept it from being deleted
+        var errors: [String: String] = [:]
+        if force && !toDelete.isEmpty {
+            if report != nil || retryFailed != nil {
+                // One save per contact, so a failure only affects that contact
+                var operations = OperationReport(command: "prune")
+                for (index, contact) in toDelete.enumerated() {
+                    do {
+                        try service.deleteContact(contact)
+                        operations.recordSuccess(index: index, id: contact.identifier, name: contact.fullName, action: "deleted")
+                    } catch {
+                        operations.recordFailure(index: index, id: contact.identifier, name: contact.fullName, action: "deleted", error: error)
+                        errors[contact.identifier] = operations.failed.last?.error
+                    }
+                }
+                if let report {
+                    try operations.save(to: report)
+                }
+            } else {
+                try service.deleteContacts(toDelete)
+            }
+        }
+
+        if json {
+            printJSON(empty, errors: errors, skipped: skipped)
+        } else if !quietOption.quiet {
+            printTable(empty, errors: errors, skipped: skipped)
+        }
+
+        if !errors.isEmpty {
+            throw ExitCode.failure
+        }
+    }
+
+    /// Ask about each candidate in turn and return the IDs of those not to delete.
+    /// Prompts go to stderr, keeping stdout for the results.
+    private func confirmDeletions(_ contacts: [CNContact]) throws -> Set<String> {
+        guard Terminal.canPrompt else {
+            throw ValidationError("--confirm-each needs a terminal to answer prompts on stdin")
+        }
+
+        var skipped = Set<String>()
+        var quit = false
+        for (index, contact) in contacts.enumerated() {
+            if quit {
+                skipped.insert(contact.identifier)
+                continue
+            }
+
+            var prompt = "\n[\(index + 1)/\(contacts.count)] \(contact.fullName.isEmpty ? "(no name)" : contact.fullName)  \(idFormatOption.displayID(contact.identifier))\n"
+            prompt += "Delete? [y/n/q] "
+
+            while true {
+                // End of input stops like q
+                let answer = Terminal.ask(prompt)?.lowercased() ?? "q"
+                if answer == "y" || answer == "yes" {
+                    break
+                }
+                if answer == "n" || answer == "no" {
+                    skipped.insert(contact.identifier)
+                    break
+                }
+                if answer == "q" || answer == "quit" {
+                    skipped.insert(contact.identifier)
+                    quit = true
+                    break
+                }
+                prompt = "Please answer y, n or q: "
+            }
+        }
+        return skipped
+    }
+
+    private func printTable(_ contacts: [CNContact], errors: [String: String], skipped: Set<String>) {
+        if contacts.isEmpty {
+            print("No empty contacts found")
+            return
+        }
+
+        for contact in contacts {
+            let name = contact.fullName.isEmpty ? "(no name)" : contact.fullName
+            var row = "\(name.padding(toLength: 20, withPad: " ", startingAt: 0))  \(idFormatOption.displayID(contact.identifier))"
+            if let error = errors[contact.identifier] {
+                row += "  (not deleted: \(error))"
+            } else if skipped.contains(contact.identifier) {
+                row += "  (skipped)"
+            }
+            print(row)
+        }
+
+        if force {
+            let failed = errors.isEmpty ? "" : ", \(errors.count) failed"
+            let kept = skipped.isEmpty ? "" : ", \(skipped.count) skipped"
+            print("\nDeleted \(contacts.count - errors.count - skipped.count) empty contact(s)\(kept)\(failed)")
+        } else {
+            print("\nFound \(contacts.count) empty contact(s). Run with --force to delete them.")
+        }
+    }
+
+    private func printJSON(_ contacts: [CNContact], errors: [String: String], skipped: Set<String>) {
+        let data = contacts.map { contact -> [String: Any] in
+            var entry: [String: Any] = [
+                "id": idFormatOption.displayID(contact.identifier),
+                "name": contact.fullName,
+                "deleted": force && errors[contact.identifier] == nil && !skipped.contains(contact.identifier),
+            ]
+            if skipped.contains(contact.identifier) {
+                entry["skipped"] = true
+            }
+            if let error = errors[contact.identifier] {
+                entry["error"] = error
+            }
+            return entry
+        }
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: .prettyPrinted),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+}
@@ -0,0 +1,10 @@
+import ArgumentParser
+import Foundation
+
+/// `--quiet` for cron jobs and scripts: no tables, footers or status lines on success.
+/// Data a command was asked for (JSON, exported cards on stdout) is still written,
+/// errors still go to stderr, and exit codes are unchanged.
+struct QuietOption: ParsableArguments {
+    @Flag(name: .shortAndLong, help: "Only print errors (and requested data such as JSON); no tables or status lines")
+    var quiet = false
+}
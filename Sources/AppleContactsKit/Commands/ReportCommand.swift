@@ -0,0 +1,212 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct Report: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "Generate reports about your contacts",
+        subcommands: [
+            Membership.self,
+            Coverage.self,
+        ]
+    )
+}
+
+extension Report {
+    struct Membership: ParsableCommand {
+        static let configuration = CommandConfiguration(
+            abstract: "Show group membership as a contact-by-group matrix",
+            discussion: """
+                Print one row per contact and one column per group, marking
+                membership with an X. Use --format csv for a spreadsheet view.
+
+                Examples:
+                  apple-contacts report membership
+                  apple-contacts report membership --format csv > membership.csv
+                  apple-contacts report membership --concurrency 8
+                """
+        )
+
+        enum Format: String, ExpressibleByArgument, CaseIterable {
+            case table
+            case csv
+            case json
+        }
+
+        @Option(name: .long, help: "Output format (table, csv, json)")
+        var format: Format = .table
+
+        @Flag(name: .long, help: "Only include contacts that belong to at least one group")
+        var groupedOnly = false
+
+        @Option(name: .long, help: "Fetch this many groups' members at a time (default: 1)")
+        var concurrency = 1
+
+        @OptionGroup var idFormatOption: IDFormatOption
+
+        @OptionGroup var quietOption: QuietOption
+
+        func validate() throws {
+            if concurrency < 1 {
+                throw ValidationError("--concurrency must be at least 1")
+            }
+        }
+
+        func run() throws {
+            let service = ContactsService()
+
+            // Check access
+            let status = CNContactStore.authorizationStatus(for: .contacts)
+            if status == .denied || status == .restricted {
+                throw ContactsError.accessDenied
+            }
+
+            let groups = try service.listGroups()
+            let memberIDs = try service.memberIDs(of: groups, concurrency: concurrency)
+            var contacts = try service.listContacts()
+
+            if groupedOnly {
+                let grouped = memberIDs.values.reduce(into: Set<String>()) { $0.formUnion($1) }
+                contacts = contacts.filter { grouped.contains($0.identifier) }
+            }
+
+            let matrix = MembershipMatrix(groups: groups, contacts: contacts, memberIDs: memberIDs)
+
+            switch format {
+            case .table:
+                if !quietOption.quiet {
+                    printTable(matrix)
+                }
+            case .csv:
+                print(matrix.csv())
+            case .json:
+                printJSON(matrix)
+            }
+        }
+
+        private func printTable(_ matrix: MembershipMatrix) {
+            if matrix.groups.isEmpty {
+                print("No groups found")
+                return
+            }
+
+            // Calculate column widths
+            let nameWidth = max(4, min(30, matrix.rows.map { $0.contact.fullName.count }.max() ?? 20))
+            let groupWidths = matrix.groups.map { max(1, $0.name.count) }
+
+            // Header
+            let header = matrix.groups.enumerated().map { i, group in
+                group.name.padding(toLength: groupWidths[i], withPad: " ", startingAt: 0)
+            }
+            print("\("NAME".padding(toLength: nameWidth, withPad: " ", startingAt: 0))  \(header.joined(separator: "  "))")
+
+            // Rows
+            for row in matrix.rows {
+                let name = String(row.contact.fullName.prefix(nameWidth)).padding(toLength: nameWidth, withPad: " ", startingAt: 0)
+                let cells = row.memberships.enumerated().map { i, member in
+                    (member ? "X" : "-").padding(toLength: groupWidths[i], withPad: " ", startingAt: 0)
+                }
+                print("\(name)  \(cells.joined(separator: "  "))")
+            }
+
+            print("\nTotal: \(matrix.rows.count) contact(s), \(matrix.groups.count) group(s)")
+        }
+
+        private func printJSON(_ matrix: MembershipMatrix) {
+            let data = matrix.rows.map { row -> [String: Any] in
+                let groups = zip(matrix.groups, row.memberships).filter { $0.1 }.map { $0.0.name }
+                return [
+                    "id": idFormatOption.displayID(row.contact.identifier),
+                    "name": row.contact.fullName,
+                    "groups": groups,
+                ]
+            }
+
+            if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: .prettyPrinted),
+               let jsonString = String(data: jsonData, encoding: .utf8)
+            {
+                print(jsonString)
+            }
+        }
+    }
+
+    struct Coverage: ParsableCommand {
+        static let configuration = CommandConfiguration(
+            commandName: "field-coverage",
+            abstract: "Show how many contacts have each field filled in",
+            discussion: """
+                For each field (nickname, organization, birthday, addresses, ...),
+                print how many contacts have it and what share of the address book
+                that is. Useful for seeing which search filters are worth using and
+                where data is missing.
+
+                The note is only included when the binary can read notes.
+
+                Examples:
+                  apple-contacts report field-coverage
+                  apple-contacts report field-coverage --json
+                """
+        )
+
+        @Flag(name: .shortAndLong, help: "Output as JSON")
+        var json = false
+
+        @OptionGroup var quietOption: QuietOption
+
+        func run() throws {
+            let service = ContactsService()
+
+            // Check access
+            let status = CNContactStore.authorizationStatus(for: .contacts)
+            if status == .denied || status == .restricted {
+                throw ContactsError.accessDenied
+            }
+
+            let contacts = try service.fetchAll(keysToFetch: ContactsService.fullKeys)
+            let notedIDs = (try? service.searchByNote(present: true)).map { Set($0.map(\.identifier)) }
+            if notedIDs == nil {
+                FileHandle.standardError.write(Data("Warning: notes are not readable; the note field is left out\n".utf8))
+            }
+
+            let coverage = FieldCoverage(contacts: contacts, notedIDs: notedIDs)
+            if json {
+                printJSON(coverage)
+            } else if !quietOption.quiet {
+                printTable(coverage)
+            }
+        }
+
+        private func printTable(_ coverage: FieldCoverage) {
+            let fieldWidth = max(5, coverage.entries.map(\.field.count).max() ?? 10)
+
+            print("\("FIELD".padding(toLength: fieldWidth, withPad: " ", startingAt: 0))  CONTACTS  COVERAGE")
+            for entry in coverage.entries {
+                let field = entry.field.padding(toLength: fieldWidth, withPad: " ", startingAt: 0)
+                let count = String(entry.count).padding(toLength: 8, withPad: " ", startingAt: 0)
+                let bar = String(repeating: "█", count: Int((entry.percent / 5).rounded()))
+                print("\(field)  \(count)  \(String(format: "%5.1f%%", entry.percent))  \(bar)")
+            }
+
+            print("\nTotal: \(coverage.total) contact(s)")
+        }
+
+        private func printJSON(_ coverage: FieldCoverage) {
+            let data: [String: Any] = [
+                "total": coverage.total,
+                "fields": coverage.entries.map { entry -> [String: Any] in
+                    [
+                        "field": entry.field,
+                        "count": entry.count,
+                        "percent": (entry.percent * 10).rounded() / 10,
+                    ]
+                },
+            ]
+
+            if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: .prettyPrinted),
+               let jsonString = String(data: jsonData, encoding: .utf8)
+            {
+                print(jsonString)
+            }
+        }
+    }
+}
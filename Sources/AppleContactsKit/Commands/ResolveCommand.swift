@@ -0,0 +1,67 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct Resolve: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "Print the IDs of contacts matching a name",
+        discussion: """
+            Resolve a name to contact IDs, printing one "ID<tab>name" line per
+            match so ambiguous names can be told apart. Uses the same fast name
+            lookup as search and exits with a failure status when nothing matches.
+
+            Examples:
+              apple-contacts resolve "Erik Fisher"
+              apple-contacts resolve fisher --json
+              apple-contacts show --id "$(apple-contacts resolve "Erik Fisher" | head -1 | cut -f1)"
+            """
+    )
+
+    @Argument(help: "Name to resolve")
+    var name: String
+
+    @Flag(name: .shortAndLong, help: "Output as JSON")
+    var json = false
+
+    @OptionGroup var globals: GlobalOptions
+
+    func run() throws {
+        let service = globals.makeService()
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        let matches = try service.searchByName(name)
+
+        if json {
+            printJSON(matches)
+        } else {
+            for contact in matches {
+                print("\(globals.displayID(contact.identifier))\t\(contact.displayName)")
+            }
+        }
+
+        if matches.isEmpty {
+            FileHandle.standardError.write(Data("No contacts match '\(name)'\n".utf8))
+            throw ExitCode.failure
+        }
+    }
+
+    private func printJSON(_ contacts: [CNContact]) {
+        let data = contacts.map { contact -> [String: Any] in
+            [
+                "id": globals.displayID(contact.identifier),
+                "name": contact.displayName,
+            ]
+        }
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: .prettyPrinted),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+}
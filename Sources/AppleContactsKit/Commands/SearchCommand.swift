@@ -0,0 +1,646 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct Search: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "Search contacts by name or other criteria",
+        discussion: """
+            Search for contacts using various criteria.
+            Without flags, searches by name and nickname (fast).
+            Use --no-nickname to match names only.
+            Multiple flags are combined with AND logic.
+
+            Examples:
+              apple-contacts search fisher
+              apple-contacts search --last-name-exact Fisher
+              apple-contacts search --email "@company.com"
+              apple-contacts search --phone "+47"
+              apple-contacts search --phone-exact "+47 900 00 000"
+              apple-contacts search --org "Acme"
+              apple-contacts search --org-exact "Acme"
+              apple-contacts search --org "Acme" --department engineering
+              apple-contacts search --address-label home --city Oslo
+              apple-contacts search --birthday 01-25
+              apple-contacts search --birthday-month 1
+              apple-contacts search --org "Acme" --has-note
+              apple-contacts search --org "Acme" --max-phones 0 --max-emails 0
+              apple-contacts search --note-key twitter --note-value @erik
+              apple-contacts search --org "Acme" --updated-within 7d
+              apple-contacts search --org "Acme" --not-in-group "Acme"
+              apple-contacts search fisher --exclude-source "On My Mac"
+              apple-contacts search --has-duplicate
+              apple-contacts search fishr --fuzzy --json --include-score
+              apple-contacts search "Smith" --json --compact
+              apple-contacts search --org Acme --format csv --output acme.csv
+              apple-contacts search jose --fold-accents
+              apple-contacts search --org "Acme" --has-note --save-query acme-notes
+              apple-contacts search --run-query acme-notes
+              apple-contacts search --org "Acme" --format-command 'jq -r .name'
+              apple-contacts search --org "Acme" --summary
+              apple-contacts search --org "Acme" --jsonpath '$[*].id'
+              apple-contacts search --json --progress-to fd3 3>progress.jsonl
+            """
+    )
+
+    @Argument(help: "Search term (searches name and nickname unless --no-nickname)")
+    var term: String?
+
+    @Option(name: .long, help: "Search by first name only (a word in it starts with the value)")
+    var firstName: String?
+
+    @Option(name: .long, help: "Search by first name only (whole name, case-insensitive)")
+    var firstNameExact: String?
+
+    @Option(name: .long, help: "Search by last name only (a word in it starts with the value)")
+    var lastName: String?
+
+    @Option(name: .long, help: "Search by last name only (whole name, case-insensitive)")
+    var lastNameExact: String?
+
+    @Option(name: .long, help: "Search by email (contains)")
+    var email: String?
+
+    @Option(name: .long, help: "Search by phone number (contains)")
+    var phone: String?
+
+    @Option(name: .long, help: "Search by phone number (whole number, compared after normalization)")
+    var phoneExact: String?
+
+    @Option(name: .long, help: "Search by organization (contains)")
+    var org: String?
+
+    @Option(name: .long, help: "Search by organization (whole name, case-insensitive)")
+    var orgExact: String?
+
+    @Option(name: .long, help: "Search by department (contains)")
+    var department: String?
+
+    @Option(name: .long, help: "Search by department (whole name, case-insensitive)")
+    var departmentExact: String?
+
+    @Option(name: .long, help: "Search in addresses (contains)")
+    var address: String?
+
+    @Option(name: .long, help: "Only match addresses with this label (home, work, or a custom label)")
+    var addressLabel: String?
+
+    @Option(name: .long, help: "Search by address city (contains)")
+    var city: String?
+
+    @Option(name: .long, help: "Search by address postal code (contains, spaces ignored)")
+    var zip: String?
+
+    @Option(name: .long, help: "Search by birthday (MM-DD format)")
+    var birthday: String?
+
+    @Option(name: .long, help: "Search by birthday month (1-12)")
+    var birthdayMonth: Int?
+
+    @Flag(name: .long, help: "Only contacts with a non-empty note")
+    var hasNote = false
+
+    @Flag(name: .long, help: "Only contacts without a note")
+    var noNote = false
+
+    @Option(name: .long, help: "Only contacts whose note has a 'key: value' line with this key")
+    var noteKey: String?
+
+    @Option(name: .long, help: "With --note-key, only contacts where that key has this value (case-insensitive)")
+    var noteValue: String?
+
+    @Flag(name: .long, help: "Match the search term against names only, not nicknames")
+    var noNickname = false
+
+    @Option(name: .long, help: "Only contacts modified within this long (e.g. 30m, 12h, 7d, 2w, 6mo)")
+    var updatedWithin: String?
+
+    @Flag(name: .long, help: "Ignore accents in the search term and names (\"Jose\" finds \"José\")")
+    var foldAccents = false
+
+    @Flag(name: .long, help: "Tolerate typos in the search term (edit-distance match on names)")
+    var fuzzy = false
+
+    @Flag(name: .long, help: "Include the fuzzy match score in JSON output (lower is better)")
+    var includeScore = false
+
+    @Option(name: .long, help: "Leave out members of this group (repeat to exclude several)")
+    var notInGroup: [String] = []
+
+    @Option(name: .long, help: "Leave out contacts stored in this account, e.g. \"On My Mac\" (repeat to exclude several)")
+    var excludeSource: [String] = []
+
+    @Flag(name: .long, help: "Only contacts whose full name is shared with another contact")
+    var hasDuplicate = false
+
+    @Option(name: .long, help: "Search across all fields")
+    var any: String?
+
+    @Option(name: .shortAndLong, help: "Limit number of results")
+    var limit: Int?
+
+    @Option(name: .long, help: "Save this search's arguments under a name in the config file")
+    var saveQuery: String?
+
+    @Option(name: .long, help: "Run a saved search (extra arguments are added to it)")
+    var runQuery: String?
+
+    @Flag(name: .long, help: "List saved searches")
+    var listQueries = false
+
+    @Flag(name: .long, help: "Add a column with the names of each contact's groups")
+    var withGroups = false
+
+    @Flag(name: .long, help: "Add a column with the account each contact is stored in, e.g. iCloud or On My Mac")
+    var showSource = false
+
+    @Flag(name: .long, help: "One line per contact: name, organization, primary phone and email")
+    var summary = false
+
+    @Flag(name: .shortAndLong, help: "Output as JSON")
+    var json = false
+
+    @OptionGroup var compactOption: CompactOption
+
+    @OptionGroup var csvOutput: CSVOutputOptions
+
+    @OptionGroup var valueCounts: ValueCountOptions
+
+    @OptionGroup var progressOption: ProgressOption
+
+    @OptionGroup var globals: GlobalOptions
+
+    func validate() throws {
+        if saveQuery != nil && runQuery != nil {
+            throw ValidationError("--save-query cannot be combined with --run-query; a saved query can't run another one")
+        }
+        if compactOption.compact && !json {
+            throw ValidationError("--compact requires --json")
+        }
+        if hasNote && noNote {
+            throw ValidationError("--has-note and --no-note cannot be used together")
+        }
+        if noteValue != nil && noteKey == nil {
+            throw ValidationError("--note-value requires --note-key")
+        }
+        if fuzzy && term == nil {
+            throw ValidationError("--fuzzy requires a search term")
+        }
+        if includeScore && !fuzzy {
+            throw ValidationError("--include-score only applies to --fuzzy searches")
+        }
+        if firstName != nil && firstNameExact != nil {
+            throw ValidationError("--first-name and --first-name-exact cannot be used together")
+        }
+        if lastName != nil && lastNameExact != nil {
+            throw ValidationError("--last-name and --last-name-exact cannot be used together")
+        }
+        if org != nil && orgExact != nil {
+            throw ValidationError("--org and --org-exact cannot be used together")
+        }
+        if department != nil && departmentExact != nil {
+            throw ValidationError("--department and --department-exact cannot be used together")
+        }
+        if let updatedWithin, ModificationDates.interval(from: updatedWithin) == nil {
+            throw ValidationError("Invalid --updated-within '\(updatedWithin)'. Use a number with m, h, d, w, mo or y, e.g. 7d")
+        }
+        if foldAccents && fuzzy {
+            throw ValidationError("--fold-accents cannot be combined with --fuzzy")
+        }
+        if noNickname && term == nil {
+            throw ValidationError("--no-nickname requires a search term")
+        }
+        if csvOutput.isCSV && (json || summary || withGroups || showSource || globals.formatCommand != nil || globals.jsonpath != nil) {
+            throw ValidationError("--format csv cannot be combined with --json, --summary, --with-groups, --show-source, --format-command or --jsonpath")
+        }
+        if summary && (json || withGroups || showSource) {
+            throw ValidationError("--summary cannot be combined with --json, --with-groups or --show-source")
+        }
+    }
+
+    func run() throws {
+        if listQueries {
+            try printQueries()
+            return
+        }
+
+        if let runQuery {
+            // Re-parse the saved arguments plus anything given alongside --run-query
+            let saved = try Self.expandQuery(runQuery, queries: Config.load().queries)
+            let command = try Search.parse(saved + Self.currentArguments(removing: "--run-query"))
+            try command.run()
+            return
+        }
+
+        let service = globals.makeService()
+        service.progress = try progressOption.makeSink()
+        let log = globals.logger(command: "search")
+
+        // Check access synchronously for CLI
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        var results: [CNContact] = []
+        var scores: [String: Int] = [:]
+
+        // Determine search type and execute
+        if let any = any {
+            results = try service.searchAll(any)
+        } else if let term = term, fuzzy {
+            // Fuzzy name search, best matches first
+            let matches = try service.searchFuzzy(term, includeNickname: !noNickname)
+            for match in matches {
+                scores[match.contact.identifier] = match.score
+            }
+            results = try applyFilters(to: matches.map { $0.contact }, service: service)
+        } else if let term = term {
+            // Name search (includes nickname)
+            var nameResults = try service.searchByName(term, includeNickname: !noNickname, foldAccents: foldAccents)
+
+            // Apply additional filters if provided
+            nameResults = try applyFilters(to: nameResults, service: service)
+            results = nameResults
+        } else if firstName != nil || firstNameExact != nil || lastName != nil || lastNameExact != nil ||
+                    email != nil || phone != nil || phoneExact != nil || org != nil || orgExact != nil ||
+                    department != nil || departmentExact != nil ||
+                    address != nil || addressLabel != nil || city != nil || zip != nil || birthday != nil || birthdayMonth != nil ||
+                    hasNote || noNote || noteKey != nil || updatedWithin != nil || !notInGroup.isEmpty || !excludeSource.isEmpty ||
+                    hasDuplicate || valueCounts.isActive
+        {
+            // Start with all contacts and filter
+            results = try service.listContacts()
+            results = try applyFilters(to: results, service: service)
+        } else {
+            throw ValidationError("Please provide a search term or use search flags (--email, --org, --has-note, etc.)")
+        }
+
+        // Filter on structured note fields; the parsed fields are also included in JSON
+        var noteFields: [String: [String: String]]?
+        if let noteKey {
+            let fields = try service.noteFields()
+            let key = noteKey.lowercased()
+            results = results.filter { contact in
+                guard let value = fields[contact.identifier]?[key] else { return false }
+                return noteValue.map { $0.caseInsensitiveCompare(value) == .orderedSame } ?? true
+            }
+            noteFields = fields
+        }
+
+        if !notInGroup.isEmpty {
+            let excluded = try service.memberIDs(ofGroupsNamed: notInGroup)
+            results = results.filter { !excluded.contains($0.identifier) }
+        }
+
+        if !excludeSource.isEmpty {
+            let excluded = try service.memberIDs(ofSourcesNamed: excludeSource)
+            results = results.filter { !excluded.contains($0.identifier) }
+        }
+
+        // Same-name contacts next to each other, each with the size of its name's cluster,
+        // ordered like list (with --locale, by that locale's collation)
+        var duplicates: [String: Int]?
+        if hasDuplicate {
+            let counts = try service.duplicateNames()
+            results = service.sortedByName(results.filter { counts[$0.fullName.lowercased()] != nil })
+            duplicates = counts
+        }
+
+        log.debug("search complete", ["matches": results.count, "elapsed_ms": log.elapsedMilliseconds])
+
+        // Apply limit
+        if let limit = limit, results.count > limit {
+            results = Array(results.prefix(limit))
+        }
+
+        // Output
+        let groupNames = withGroups ? try service.groupNames() : nil
+        let sourceNames = showSource ? try service.sourceNames() : nil
+        var formatFailures = 0
+        if let formatter = globals.externalFormatter(log: log) {
+            formatFailures = formatter.run(
+                jsonEntries(results, scores: includeScore ? scores : nil, groupNames: groupNames, sourceNames: sourceNames, noteFields: noteFields, duplicates: duplicates)
+            )
+        } else if let path = globals.jsonPath {
+            try globals.printJSONPath(
+                path,
+                in: jsonEntries(results, scores: includeScore ? scores : nil, groupNames: groupNames, sourceNames: sourceNames, noteFields: noteFields, duplicates: duplicates)
+            )
+        } else if json {
+            printJSON(results, scores: includeScore ? scores : nil, groupNames: groupNames, sourceNames: sourceNames, noteFields: noteFields, duplicates: duplicates)
+        } else if csvOutput.isCSV {
+            let detailed = try service.getContacts(
+                ids: results.map(\.identifier),
+                keysToFetch: ContactsService.basicKeys + CSVOutputOptions.keys
+            )
+            try csvOutput.write(detailed, redaction: globals.redaction, displayID: globals.displayID, nameLocale: globals.nameLocale)
+        } else if globals.quiet {
+            // No table in --quiet mode
+        } else if summary {
+            let detailed = try service.getContacts(
+                ids: results.map(\.identifier),
+                keysToFetch: ContactsService.basicKeys + CNContact.summaryKeys
+            )
+            printSummary(detailed)
+        } else {
+            printTable(results, groupNames: groupNames, sourceNames: sourceNames, duplicates: duplicates)
+        }
+
+        if let saveQuery {
+            var config = try Config.load()
+            config.queries[saveQuery] = Self.currentArguments(removing: "--save-query")
+            try config.save()
+            if !globals.quiet {
+                FileHandle.standardError.write(Data("Saved query '\(saveQuery)' to \(Config.fileURL.path)\n".utf8))
+            }
+        }
+
+        log.finish()
+
+        if formatFailures > 0 {
+            throw ExitCode.failure
+        }
+    }
+
+    /// A saved query's arguments. A --run-query in them (only possible by editing the config
+    /// file, since --save-query refuses it) is replaced by that query's arguments; a chain
+    /// that leads back to a query already being expanded throws instead of recursing forever.
+    private static func expandQuery(_ name: String, queries: [String: [String]], visited: [String] = []) throws -> [String] {
+        if visited.contains(name) {
+            throw ContactsError.queryCycle(visited + [name])
+        }
+        guard let saved = queries[name] else {
+            throw ContactsError.queryNotFound(name)
+        }
+
+        var result: [String] = []
+        var index = saved.startIndex
+        while index < saved.endIndex {
+            let argument = saved[index]
+            if argument == "--run-query", index + 1 < saved.endIndex {
+                result += try expandQuery(saved[index + 1], queries: queries, visited: visited + [name])
+                index += 2
+                continue
+            }
+            if argument.hasPrefix("--run-query=") {
+                let nested = String(argument.dropFirst("--run-query=".count))
+                result += try expandQuery(nested, queries: queries, visited: visited + [name])
+            } else {
+                result.append(argument)
+            }
+            index += 1
+        }
+        return result
+    }
+
+    /// Arguments given to this subcommand, without the named option and its value.
+    /// The subcommand is the first argument after the executable that isn't an option
+    /// (the root command takes none), so a search term or option value that happens
+    /// to be "search" is never mistaken for it.
+    private static func currentArguments(removing option: String) -> [String] {
+        let all = CommandLine.arguments.dropFirst()
+        guard let start = all.firstIndex(where: { !$0.hasPrefix("-") }) else {
+            return []
+        }
+
+        var result: [String] = []
+        var skipNext = false
+        for argument in all[(start + 1)...] {
+            if skipNext {
+                skipNext = false
+            } else if argument == option {
+                skipNext = true
+            } else if !argument.hasPrefix(option + "=") {
+                result.append(argument)
+            }
+        }
+        return result
+    }
+
+    private func printQueries() throws {
+        let queries = try Config.load().queries
+        if queries.isEmpty {
+            print("No saved queries")
+            return
+        }
+
+        let nameWidth = max(4, queries.keys.map(\.count).max() ?? 10)
+        print("\("NAME".padding(toLength: nameWidth, withPad: " ", startingAt: 0))  ARGUMENTS")
+        for name in queries.keys.sorted() {
+            let arguments = queries[name, default: []]
+                .map { $0.contains(" ") ? "\"\($0)\"" : $0 }
+                .joined(separator: " ")
+            print("\(name.padding(toLength: nameWidth, withPad: " ", startingAt: 0))  \(arguments)")
+        }
+    }
+
+    private func applyFilters(to contacts: [CNContact], service: ContactsService) throws -> [CNContact] {
+        var filtered = contacts
+
+        if let first = firstNameExact ?? firstName {
+            let matches = Set(
+                try service.searchByNamePart(first, part: \.givenName, exact: firstNameExact != nil, foldAccents: foldAccents)
+                    .map(\.identifier)
+            )
+            filtered = filtered.filter { matches.contains($0.identifier) }
+        }
+
+        if let last = lastNameExact ?? lastName {
+            let matches = Set(
+                try service.searchByNamePart(last, part: \.familyName, exact: lastNameExact != nil, foldAccents: foldAccents)
+                    .map(\.identifier)
+            )
+            filtered = filtered.filter { matches.contains($0.identifier) }
+        }
+
+        if let email = email {
+            let emailMatches = Set(try service.searchByEmail(email).map(\.identifier))
+            filtered = filtered.filter { emailMatches.contains($0.identifier) }
+        }
+
+        if let phone = phone {
+            let phoneMatches = Set(try service.searchByPhone(phone, region: globals.resolvedPhoneRegion).map(\.identifier))
+            filtered = filtered.filter { phoneMatches.contains($0.identifier) }
+        }
+
+        if let phoneExact {
+            let phoneMatches = Set(try service.searchByPhone(phoneExact, region: globals.resolvedPhoneRegion, exact: true).map(\.identifier))
+            filtered = filtered.filter { phoneMatches.contains($0.identifier) }
+        }
+
+        if let org = org {
+            let orgMatches = Set(try service.searchByOrganization(org, foldAccents: foldAccents).map(\.identifier))
+            filtered = filtered.filter { orgMatches.contains($0.identifier) }
+        }
+
+        if let orgExact {
+            let orgMatches = Set(try service.searchByOrganization(orgExact, exact: true, foldAccents: foldAccents).map(\.identifier))
+            filtered = filtered.filter { orgMatches.contains($0.identifier) }
+        }
+
+        if let department = departmentExact ?? department {
+            let departmentMatches = Set(
+                try service.searchByDepartment(department, exact: departmentExact != nil, foldAccents: foldAccents).map(\.identifier)
+            )
+            filtered = filtered.filter { departmentMatches.contains($0.identifier) }
+        }
+
+        if address != nil || addressLabel != nil || city != nil || zip != nil {
+            let addrMatches = Set(
+                try service.searchByAddress(address, label: addressLabel, city: city, postalCode: zip).map(\.identifier)
+            )
+            filtered = filtered.filter { addrMatches.contains($0.identifier) }
+        }
+
+        if let birthday = birthday {
+            // Parse MM-DD format
+            let parts = birthday.split(separator: "-")
+            if parts.count == 2, let month = Int(parts[0]), let day = Int(parts[1]) {
+                let bdayMatches = Set(try service.searchByBirthday(month: month, day: day).map(\.identifier))
+                filtered = filtered.filter { bdayMatches.contains($0.identifier) }
+            }
+        }
+
+        if let birthdayMonth = birthdayMonth {
+            let bdayMatches = Set(try service.searchByBirthday(month: birthdayMonth, day: nil).map(\.identifier))
+            filtered = filtered.filter { bdayMatches.contains($0.identifier) }
+        }
+
+        if hasNote || noNote {
+            let noteMatches = Set(try service.searchByNote(present: hasNote).map(\.identifier))
+            filtered = filtered.filter { noteMatches.contains($0.identifier) }
+        }
+
+        if valueCounts.isActive {
+            let counts = try service.valueCounts()
+            filtered = filtered.filter { counts[$0.identifier].map(valueCounts.matches) ?? false }
+        }
+
+        if let updatedWithin, let interval = ModificationDates.interval(from: updatedWithin) {
+            let cutoff = Date().addingTimeInterval(-interval)
+            let dates = try ModificationDates.all()
+            filtered = filtered.filter { dates[$0.identifier].map { $0 >= cutoff } ?? false }
+        }
+
+        return filtered
+    }
+
+    private func printTable(
+        _ contacts: [CNContact],
+        groupNames: [String: [String]]? = nil,
+        sourceNames: [String: [String]]? = nil,
+        duplicates: [String: Int]? = nil
+    ) {
+        if contacts.isEmpty {
+            print("No contacts found")
+            return
+        }
+
+        // Calculate column widths
+        let names = contacts.map { $0.fullName(locale: globals.nameLocale) }
+        let nameWidth = max(4, names.map(\.count).max() ?? 20)
+        let nickWidth = max(8, contacts.map { $0.nickname.count }.max() ?? 10)
+        let groupsWidth = groupNames.map { names in
+            max(6, min(30, contacts.map { (names[$0.identifier] ?? []).joined(separator: ", ").count }.max() ?? 10))
+        }
+        let sourceWidth = sourceNames.map { names in
+            max(9, min(30, contacts.map { ContactsService.sourceLabel(names[$0.identifier]).count }.max() ?? 10))
+        }
+
+        // Header
+        var header = "\("NAME".padding(toLength: nameWidth, withPad: " ", startingAt: 0))  \("NICKNAME".padding(toLength: nickWidth, withPad: " ", startingAt: 0))  "
+        if let groupsWidth {
+            header += "\("GROUPS".padding(toLength: groupsWidth, withPad: " ", startingAt: 0))  "
+        }
+        if let sourceWidth {
+            header += "\("SOURCE".padding(toLength: sourceWidth, withPad: " ", startingAt: 0))  "
+        }
+        if duplicates != nil {
+            header += "SAME NAME  "
+        }
+        print(header + "ID")
+
+        // Rows
+        for (index, contact) in contacts.enumerated() {
+            let name = names[index].padding(toLength: nameWidth, withPad: " ", startingAt: 0)
+            let nick = (contact.nickname.isEmpty ? "-" : contact.nickname).padding(toLength: nickWidth, withPad: " ", startingAt: 0)
+
+            var row = "\(name)  \(nick)  "
+            if let groupNames, let groupsWidth {
+                let groups = (groupNames[contact.identifier] ?? []).joined(separator: ", ")
+                row += "\((groups.isEmpty ? "-" : String(groups.prefix(groupsWidth))).padding(toLength: groupsWidth, withPad: " ", startingAt: 0))  "
+            }
+            if let sourceNames, let sourceWidth {
+                let source = ContactsService.sourceLabel(sourceNames[contact.identifier])
+                row += "\(String(source.prefix(sourceWidth)).padding(toLength: sourceWidth, withPad: " ", startingAt: 0))  "
+            }
+            if let duplicates {
+                row += "\(String(duplicates[contact.fullName.lowercased()] ?? 0).padding(toLength: 9, withPad: " ", startingAt: 0))  "
+            }
+            print(row + globals.displayID(contact.identifier))
+        }
+
+        print("\nFound \(contacts.count) contact(s)")
+    }
+
+    private func printSummary(_ contacts: [CNContact]) {
+        let redaction = globals.redaction
+        for contact in contacts {
+            print(contact.summaryLine(redaction: redaction, nameLocale: globals.nameLocale))
+        }
+    }
+
+    private func jsonEntries(
+        _ contacts: [CNContact],
+        scores: [String: Int]? = nil,
+        groupNames: [String: [String]]? = nil,
+        sourceNames: [String: [String]]? = nil,
+        noteFields: [String: [String: String]]? = nil,
+        duplicates: [String: Int]? = nil
+    ) -> [[String: Any]] {
+        contacts.map { contact -> [String: Any] in
+            var entry: [String: Any] = [
+                "id": globals.displayID(contact.identifier),
+                "name": contact.fullName(locale: globals.nameLocale),
+                "firstName": contact.givenName,
+                "lastName": contact.familyName,
+                "nickname": contact.nickname,
+                "organization": contact.organizationName,
+            ]
+            if let score = scores?[contact.identifier] {
+                entry["score"] = score
+            }
+            if let groupNames {
+                entry["groups"] = groupNames[contact.identifier] ?? []
+            }
+            if let sourceNames {
+                entry["source"] = ContactsService.sourceLabel(sourceNames[contact.identifier])
+            }
+            if let noteFields {
+                entry["noteFields"] = noteFields[contact.identifier] ?? [:]
+            }
+            if let duplicates {
+                entry["sameNameCount"] = duplicates[contact.fullName.lowercased()] ?? 0
+            }
+            return entry
+        }
+    }
+
+    private func printJSON(
+        _ contacts: [CNContact],
+        scores: [String: Int]? = nil,
+        groupNames: [String: [String]]? = nil,
+        sourceNames: [String: [String]]? = nil,
+        noteFields: [String: [String: String]]? = nil,
+        duplicates: [String: Int]? = nil
+    ) {
+        let data = jsonEntries(contacts, scores: scores, groupNames: groupNames, sourceNames: sourceNames, noteFields: noteFields, duplicates: duplicates)
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: compactOption.writingOptions()),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+}
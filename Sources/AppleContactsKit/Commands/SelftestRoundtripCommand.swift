@@ -0,0 +1,107 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct SelftestRoundtrip: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "selftest-roundtrip",
+        abstract: "Check that a contact survives a vCard export and re-import",
+        discussion: """
+            Developer tool for checking the export pipeline. Exports the contact
+            as vCard the same way 'export' does, parses the card back, and lists
+            every field whose values changed on the way. Nothing is saved.
+
+            Compared: names, nickname, organization, department, job title,
+            birthday, phones, emails, addresses, URLs, social profiles, instant
+            messages, related names, other dates, photo and, with the notes
+            entitlement, the note. The output lists the fields that were compared.
+
+            Exits with status 1 when any field differs.
+
+            Examples:
+              apple-contacts selftest-roundtrip --id ABC123...
+              apple-contacts selftest-roundtrip --id ABC123... --json
+            """,
+        shouldDisplay: false
+    )
+
+    @Option(name: .long, help: "Contact ID")
+    var id: String
+
+    @Flag(name: .shortAndLong, help: "Output as JSON")
+    var json = false
+
+    @OptionGroup var idFormatOption: IDFormatOption
+
+    @OptionGroup var quietOption: QuietOption
+
+    func run() throws {
+        let service = ContactsService()
+        // Compare against what is stored, not the normalized display form
+        service.useStoredValues()
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        guard let found = try service.getContact(id: id) else {
+            throw ContactsError.contactNotFound
+        }
+        // Fetch the fields the comparison reads beyond fullKeys. Without the notes
+        // entitlement the note can't be read, so it is left out of the comparison.
+        let keys = ContactsService.fullKeys + ContactComparison.extraKeys
+        let withNote = try? service.getContacts(ids: [found.identifier], keysToFetch: keys + [CNContactNoteKey as CNKeyDescriptor])
+        guard let original = try withNote?.first ?? service.getContacts(ids: [found.identifier], keysToFetch: keys).first else {
+            throw ContactsError.contactNotFound
+        }
+
+        let vcard = try service.exportVCard(contact: original)
+        guard let parsed = try CNContactVCardSerialization.contacts(with: vcard).first else {
+            throw ContactsError.exportFailed
+        }
+
+        let diffs = ContactComparison.compare(original, parsed).filter(\.differs)
+        let compared = ContactComparison.comparedFields(original, parsed)
+
+        if json {
+            printJSON(original, diffs, compared: compared)
+        } else if quietOption.quiet {
+            // The exit status says whether the card survived
+        } else if diffs.isEmpty {
+            print("✓ \(original.displayName): all compared fields survived the round trip")
+            print("  compared: \(compared.joined(separator: ", "))")
+        } else {
+            print("✗ \(original.displayName): \(diffs.count) field(s) changed in the round trip\n")
+            for diff in diffs {
+                print("\(diff.field):")
+                print("  exported: \(diff.left.isEmpty ? "-" : diff.left.joined(separator: "; "))")
+                print("  parsed:   \(diff.right.isEmpty ? "-" : diff.right.joined(separator: "; "))")
+            }
+            print("\ncompared: \(compared.joined(separator: ", "))")
+        }
+
+        if !diffs.isEmpty {
+            throw ExitCode.failure
+        }
+    }
+
+    private func printJSON(_ contact: CNContact, _ diffs: [FieldDiff], compared: [String]) {
+        let data: [String: Any] = [
+            "id": idFormatOption.displayID(contact.identifier),
+            "name": contact.displayName,
+            "ok": diffs.isEmpty,
+            "compared": compared,
+            "differences": diffs.map { diff -> [String: Any] in
+                ["field": diff.field, "exported": diff.left, "parsed": diff.right]
+            },
+        ]
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: [.prettyPrinted, .sortedKeys]),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+}
@@ -0,0 +1,567 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+struct Show: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        abstract: "Show full details for a contact",
+        discussion: """
+            Display all available information for a specific contact.
+            You can identify the contact by name or ID.
+
+            Examples:
+              apple-contacts show "John Doe"
+              apple-contacts show --id ABC123...
+              apple-contacts show "John Doe" --qr
+              apple-contacts show "John Doe" --raw-vcard
+              apple-contacts show fisher --first
+              apple-contacts show "John Doe" --jsonpath '$.phones[0].value'
+              apple-contacts show "John Doe" --stale-after 2y
+              apple-contacts show --compare "Erik F" "Erik Fisher"
+              apple-contacts show "Erik Fisher" --open-maps
+              apple-contacts show "Erik Fisher" --open-maps --address-index 2
+            """
+    )
+
+    @Argument(help: "Contact name to look up")
+    var name: String?
+
+    @Option(name: .long, help: "Contact ID (use if name is ambiguous)")
+    var id: String?
+
+    @Flag(name: .long, help: "Show the first contact matching the name, without looking for an exact match (faster)")
+    var first = false
+
+    @Option(
+        name: .long,
+        parsing: .upToNextOption,
+        help: "Show two contacts side by side, marking differences (two names or IDs)"
+    )
+    var compare: [String] = []
+
+    @Flag(name: .shortAndLong, help: "Output as JSON")
+    var json = false
+
+    @OptionGroup var compactOption: CompactOption
+
+    @Option(name: .long, help: "Show at most this many values per section (phones, emails, ...); affects show only, not search or export")
+    var maxValues: Int?
+
+    @Flag(name: .long, help: "Group values with the same label under one heading")
+    var collapseLabels = false
+
+    @Option(name: .long, help: "Warn when the contact hasn't been modified for this long (e.g. 6mo, 2y)")
+    var staleAfter: String?
+
+    @Flag(name: .long, help: "Also show the contact's vCard as a scannable QR code")
+    var qr = false
+
+    @Flag(name: .long, help: "Append the contact's vCard as exported (JSON: a vcard field)")
+    var rawVcard = false
+
+    @Flag(name: .long, help: "Open the contact's address in Apple Maps (asks which one when there are several)")
+    var openMaps = false
+
+    @Option(name: .long, help: "With --open-maps, open this address (1 = first) without asking")
+    var addressIndex: Int?
+
+    @OptionGroup var labelOptions: LabelOrderOptions
+
+    @OptionGroup var globals: GlobalOptions
+
+    func validate() throws {
+        if compactOption.compact && !json {
+            throw ValidationError("--compact requires --json")
+        }
+        if !compare.isEmpty {
+            if compare.count != 2 {
+                throw ValidationError("--compare takes exactly two names or IDs")
+            }
+            if name != nil || id != nil || qr || rawVcard {
+                throw ValidationError("--compare cannot be combined with a name, --id, --qr or --raw-vcard")
+            }
+        }
+        if openMaps && !compare.isEmpty {
+            throw ValidationError("--open-maps cannot be combined with --compare")
+        }
+        if let addressIndex {
+            if !openMaps {
+                throw ValidationError("--address-index requires --open-maps")
+            }
+            if addressIndex < 1 {
+                throw ValidationError("--address-index must be at least 1")
+            }
+        }
+        if rawVcard && globals.redact != nil {
+            // The card would carry every value --redact is meant to hide
+            throw ValidationError("--raw-vcard cannot be combined with --redact")
+        }
+        if first && name == nil {
+            throw ValidationError("--first requires a contact name")
+        }
+        if let maxValues, maxValues < 1 {
+            throw ValidationError("--max-values must be at least 1")
+        }
+        if let staleAfter, ModificationDates.interval(from: staleAfter, allowedUnits: ModificationDates.longUnits) == nil {
+            let lower = staleAfter.lowercased()
+            if lower.hasSuffix("m") || lower.hasSuffix("h") {
+                throw ValidationError(
+                    "Invalid --stale-after '\(staleAfter)'. Minutes and hours aren't supported; for months use mo, e.g. \(staleAfter.dropLast())mo"
+                )
+            }
+            throw ValidationError("Invalid --stale-after '\(staleAfter)'. Use a number with d, w, mo or y, e.g. 2y")
+        }
+    }
+
+    func run() throws {
+        let service = globals.makeService()
+        service.labelOrder = labelOptions.resolvedOrder
+        let log = globals.logger(command: "show")
+
+        // Check access
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        if status == .denied || status == .restricted {
+            throw ContactsError.accessDenied
+        }
+
+        if !compare.isEmpty {
+            try runCompare(service: service)
+            log.finish()
+            return
+        }
+
+        var contact: CNContact?
+
+        if let id = id {
+            contact = try service.getContact(id: id)
+        } else if let name = name {
+            contact = first ? try service.getFirstContact(name: name) : try service.getContact(name: name)
+        } else {
+            throw ValidationError("Please provide a contact name or --id")
+        }
+
+        guard let contact else {
+            throw ContactsError.contactNotFound
+        }
+        log.debug("fetched contact", ["id": contact.identifier, "elapsed_ms": log.elapsedMilliseconds])
+
+        if openMaps {
+            try openInMaps(contact)
+        }
+
+        let vcard = rawVcard ? try service.exportVCardString(contact: contact) : nil
+
+        if let formatter = globals.externalFormatter(log: log) {
+            if formatter.run([jsonObject(contact, vcard: vcard)]) > 0 {
+                throw ExitCode.failure
+            }
+            log.finish()
+            return
+        }
+
+        if let path = globals.jsonPath {
+            try globals.printJSONPath(path, in: jsonObject(contact, vcard: vcard))
+            log.finish()
+            return
+        }
+
+        if json {
+            printJSON(contact, vcard: vcard)
+        } else {
+            // Nothing to show in --quiet mode; the exit status still says whether the contact exists
+            guard !globals.quiet else {
+                log.finish()
+                return
+            }
+            printDetails(contact)
+            try printStaleWarning(contact)
+            if let vcard {
+                print("\nVCARD:")
+                print(vcard, terminator: vcard.hasSuffix("\n") ? "" : "\n")
+            }
+        }
+
+        if qr {
+            try printQRCode(contact, service: service, log: log)
+        }
+
+        log.finish()
+    }
+
+    /// Open one of the contact's non-empty addresses in Maps: the --address-index one,
+    /// the only one, or the one the user picks
+    private func openInMaps(_ contact: CNContact) throws {
+        let addresses = contact.postalAddresses.filter { !Maps.singleLine($0.value).isEmpty }
+        guard !addresses.isEmpty else {
+            throw ContactsError.noAddress
+        }
+
+        let index: Int
+        if let addressIndex {
+            guard addressIndex <= addresses.count else {
+                throw ContactsError.addressIndexOutOfRange(addressIndex, count: addresses.count)
+            }
+            index = addressIndex - 1
+        } else if addresses.count == 1 {
+            index = 0
+        } else {
+            index = try promptForAddress(addresses)
+        }
+
+        try Maps.open(addresses[index].value)
+    }
+
+    /// Ask which address to open. The prompt goes to stderr, keeping stdout for the contact
+    private func promptForAddress(_ addresses: [CNLabeledValue<CNPostalAddress>]) throws -> Int {
+        guard Terminal.canPrompt else {
+            throw ValidationError("The contact has \(addresses.count) addresses; choose one with --address-index")
+        }
+
+        var prompt = "Open which address?\n"
+        for (i, address) in addresses.enumerated() {
+            prompt += "  \(i + 1)) \(LabelOrder.displayLabel(address.label)): \(Maps.singleLine(address.value))\n"
+        }
+        prompt += "> "
+
+        guard let line = Terminal.ask(prompt),
+              let choice = Int(line), (1...addresses.count).contains(choice)
+        else {
+            throw ValidationError("Please enter a number from 1 to \(addresses.count)")
+        }
+        return choice - 1
+    }
+
+    private func printQRCode(_ contact: CNContact, service: ContactsService, log: Logger) throws {
+        // Don't write block characters into pipes or JSON consumers
+        guard Terminal.isInteractive, !json else {
+            log.warning("QR code skipped: output is not a terminal")
+            return
+        }
+
+        // The code would carry the full card, defeating --redact
+        guard redaction.fields.isEmpty else {
+            log.warning("QR code skipped: --redact is set")
+            return
+        }
+
+        let vcard = try service.exportVCard(contact: contact)
+        guard let code = QRCode(data: vcard) else {
+            throw ValidationError("Contact card is too large to fit in a QR code")
+        }
+
+        print("\nQR (scan to add contact):")
+        print(code.render(color: globals.useColor))
+    }
+
+    /// Show the two --compare contacts side by side
+    private func runCompare(service: ContactsService) throws {
+        let contacts = try compare.map { value -> CNContact in
+            // Values shaped like contact IDs are looked up as IDs, anything else as a name
+            let looksLikeID = value.contains(":") || value.range(of: #"^[0-9A-Fa-f]{8}-[0-9A-Fa-f-]+$"#, options: .regularExpression) != nil
+            let found = looksLikeID ? try service.getContact(id: value) : try service.getContact(name: value)
+            guard let found else {
+                throw ContactsError.contactNotFound
+            }
+            return found
+        }
+        let (left, right) = (contacts[0], contacts[1])
+        let diffs = ContactComparison.compare(left, right, redaction: redaction)
+
+        if json {
+            printComparisonJSON(left, right, diffs)
+        } else if !globals.quiet {
+            printComparison(left, right, diffs)
+        }
+    }
+
+    private func printComparison(_ left: CNContact, _ right: CNContact, _ diffs: [FieldDiff]) {
+        let leftName = left.displayName(locale: globals.nameLocale)
+        let fieldWidth = max(5, diffs.map(\.field.count).max() ?? 5)
+        let columnWidth = min(36, max(leftName.count, diffs.flatMap(\.left).map(\.count).max() ?? 0, 4))
+
+        func cell(_ text: String) -> String {
+            let clipped = text.count > columnWidth ? String(text.prefix(columnWidth - 1)) + "…" : text
+            return clipped.padding(toLength: columnWidth, withPad: " ", startingAt: 0)
+        }
+
+        print("  \("FIELD".padding(toLength: fieldWidth, withPad: " ", startingAt: 0))  \(cell(leftName))  \(right.displayName(locale: globals.nameLocale))")
+        for diff in diffs {
+            let marker = diff.differs ? "*" : " "
+            for line in 0..<max(diff.left.count, diff.right.count, 1) {
+                let field = line == 0 ? diff.field : ""
+                let leftValue = line < diff.left.count ? diff.left[line] : (line == 0 ? "-" : "")
+                let rightValue = line < diff.right.count ? diff.right[line] : (line == 0 ? "-" : "")
+                var row = "\(line == 0 ? marker : " ") \(field.padding(toLength: fieldWidth, withPad: " ", startingAt: 0))  \(cell(leftValue))  \(rightValue)"
+                if diff.differs && globals.useColor {
+                    row = "\u{1B}[33m\(row)\u{1B}[0m"
+                }
+                print(row)
+            }
+        }
+
+        let differing = diffs.filter(\.differs).count
+        print("\n\(differing) of \(diffs.count) field(s) differ (marked *)")
+    }
+
+    private func printComparisonJSON(_ left: CNContact, _ right: CNContact, _ diffs: [FieldDiff]) {
+        let data: [String: Any] = [
+            "left": ["id": globals.displayID(left.identifier), "name": left.displayName(locale: globals.nameLocale)],
+            "right": ["id": globals.displayID(right.identifier), "name": right.displayName(locale: globals.nameLocale)],
+            "fields": diffs.map { diff -> [String: Any] in
+                [
+                    "field": diff.field,
+                    "left": diff.left,
+                    "right": diff.right,
+                    "differs": diff.differs,
+                ]
+            },
+        ]
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: compactOption.writingOptions(.sortedKeys)),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+
+    /// With --stale-after, warn when the contact was last modified longer ago than that
+    private func printStaleWarning(_ contact: CNContact) throws {
+        guard let staleAfter, let interval = ModificationDates.interval(from: staleAfter, allowedUnits: ModificationDates.longUnits),
+              let modified = try ModificationDates.date(for: contact.identifier),
+              modified < Date().addingTimeInterval(-interval)
+        else {
+            return
+        }
+
+        let warning = "⚠ Not updated since \(globals.dateDisplay.date(modified))"
+        print("\n" + (globals.useColor ? "\u{1B}[33m\(warning)\u{1B}[0m" : warning))
+    }
+
+    private var redaction: Redaction {
+        globals.redaction
+    }
+
+    /// Apply --max-values to a section, returning the kept values and how many were left out
+    private func capped<T>(_ values: [T]) -> (values: [T], omitted: Int) {
+        Self.capped(values, to: maxValues)
+    }
+
+    /// Keep at most `limit` values (all of them when nil). Only `show` caps sections;
+    /// search and export always output every value.
+    static func capped<T>(_ values: [T], to limit: Int?) -> (values: [T], omitted: Int) {
+        guard let limit, values.count > limit else {
+            return (values, 0)
+        }
+        return (Array(values.prefix(limit)), values.count - limit)
+    }
+
+    private func printOmitted(_ count: Int) {
+        if count > 0 {
+            print("  (+\(count) more)")
+        }
+    }
+
+    /// Print a section of labeled values, one per line, or grouped under each label with --collapse-labels.
+    /// With colors on, labels are dimmed and the primary value is bold; padding is worked
+    /// out on the plain text, so the layout is the same either way. The primary value is the
+    /// first by label preference, as in `summaryLine`, not the first listed: with stable
+    /// ordering the list is alphabetical.
+    private func printSection<T: NSCopying & NSSecureCoding>(
+        _ title: String,
+        _ values: [CNLabeledValue<T>],
+        entry: (CNLabeledValue<T>) -> (label: String, value: String)
+    ) {
+        if values.isEmpty { return }
+
+        print("\n\(title):")
+        let section = capped(values)
+        let order = labelOptions.resolvedOrder ?? LabelOrder.defaultOrder
+        let primaryID = LabelOrder.sortLabeledByPreference(values, order: order).first?.identifier
+        let entries = section.values.map { value in
+            let (label, text) = entry(value)
+            return (label: label, value: text, primary: value.identifier == primaryID)
+        }
+        let color = globals.useColor
+        func label(_ text: String) -> String {
+            color ? "\u{1B}[2m\(text)\u{1B}[0m" : text
+        }
+        func value(_ text: String, primary: Bool) -> String {
+            color && primary ? "\u{1B}[1m\(text)\u{1B}[0m" : text
+        }
+
+        if collapseLabels {
+            // Group by label, keeping the order in which labels first appear
+            var labels: [String] = []
+            var grouped: [String: [(value: String, primary: Bool)]] = [:]
+            for entry in entries {
+                if grouped[entry.label] == nil {
+                    labels.append(entry.label)
+                }
+                grouped[entry.label, default: []].append((entry.value, entry.primary))
+            }
+            for name in labels {
+                print("  \(label(name))")
+                for item in grouped[name, default: []] {
+                    print("    \(value(item.value, primary: item.primary))")
+                }
+            }
+        } else {
+            for entry in entries {
+                print("  \(label(entry.label.padding(toLength: 12, withPad: " ", startingAt: 0))) \(value(entry.value, primary: entry.primary))")
+            }
+        }
+
+        printOmitted(section.omitted)
+    }
+
+    private func printDetails(_ contact: CNContact) {
+        // Basic info
+        let fullName = contact.fullName(locale: globals.nameLocale)
+        if let phonetic = contact.phoneticName {
+            print("Name:         \(fullName) (\(phonetic))")
+        } else {
+            print("Name:         \(fullName)")
+        }
+
+        if !contact.nickname.isEmpty {
+            print("Nickname:     \(contact.nickname)")
+        }
+
+        if !contact.organizationName.isEmpty {
+            print("Organization: \(contact.organizationName)")
+        }
+
+        if !contact.departmentName.isEmpty {
+            print("Department:   \(contact.departmentName)")
+        }
+
+        if !contact.jobTitle.isEmpty {
+            print("Job Title:    \(contact.jobTitle)")
+        }
+
+        if let birthday = globals.dateDisplay.birthday(of: contact) {
+            print("Birthday:     \(redaction.birthday(birthday))")
+        }
+
+        printSection("PHONES", contact.phoneNumbers) { phone in
+            (LabelOrder.displayLabel(phone.label), redaction.phone(phone.value.stringValue))
+        }
+
+        printSection("EMAILS", contact.emailAddresses) { email in
+            (LabelOrder.displayLabel(email.label), redaction.email(email.value as String))
+        }
+
+        printSection("ADDRESSES", contact.postalAddresses) { address in
+            let formatted = redaction.address(address.value)
+                .replacingOccurrences(of: "\n", with: ", ")
+            return (LabelOrder.displayLabel(address.label), formatted)
+        }
+
+        printSection("URLS", contact.urlAddresses) { url in
+            (LabelOrder.displayLabel(url.label), url.value as String)
+        }
+
+        printSection("SOCIAL", contact.socialProfiles) { profile in
+            (profile.value.service, profile.value.username)
+        }
+
+        printSection("RELATIONS", contact.contactRelations) { relation in
+            (LabelOrder.displayLabel(relation.label), relation.value.name)
+        }
+
+        // ID
+        print("\nID: \(globals.displayID(contact.identifier))")
+    }
+
+    private func jsonObject(_ contact: CNContact, vcard: String? = nil) -> [String: Any] {
+        var data: [String: Any] = [
+            "id": globals.displayID(contact.identifier),
+            "name": contact.fullName(locale: globals.nameLocale),
+            "firstName": contact.givenName,
+            "lastName": contact.familyName,
+            "middleName": contact.middleName,
+            "phoneticFirstName": contact.phoneticGivenName,
+            "phoneticMiddleName": contact.phoneticMiddleName,
+            "phoneticLastName": contact.phoneticFamilyName,
+            "nickname": contact.nickname,
+            "organization": contact.organizationName,
+            "department": contact.departmentName,
+            "jobTitle": contact.jobTitle,
+        ]
+
+        if let birthday = contact.birthdayString {
+            data["birthday"] = redaction.birthday(birthday)
+        }
+
+        let region = globals.resolvedPhoneRegion
+        data["phones"] = capped(contact.phoneNumbers).values.map { phone -> [String: String] in
+            [
+                "label": LabelOrder.displayLabel(phone.label),
+                "value": redaction.phone(phone.value.stringValue),
+                "normalized": redaction.phone(PhoneNumbers.normalize(phone.value.stringValue, region: region)),
+            ]
+        }
+
+        data["emails"] = capped(contact.emailAddresses).values.map { email -> [String: String] in
+            [
+                "label": LabelOrder.displayLabel(email.label),
+                "value": redaction.email(email.value as String),
+            ]
+        }
+
+        data["addresses"] = capped(contact.postalAddresses).values.map { address -> [String: String] in
+            [
+                "label": LabelOrder.displayLabel(address.label),
+                "value": redaction.address(address.value),
+            ]
+        }
+
+        data["urls"] = capped(contact.urlAddresses).values.map { url -> [String: String] in
+            [
+                "label": LabelOrder.displayLabel(url.label),
+                "value": url.value as String,
+            ]
+        }
+
+        data["socialProfiles"] = capped(contact.socialProfiles).values.map { profile -> [String: String] in
+            [
+                "service": profile.value.service,
+                "username": profile.value.username,
+            ]
+        }
+
+        data["relations"] = capped(contact.contactRelations).values.map { relation -> [String: String] in
+            [
+                "label": LabelOrder.displayLabel(relation.label),
+                "name": relation.value.name,
+            ]
+        }
+
+        // Report how many values --max-values left out of each section
+        let omitted: [String: Int] = [
+            "phones": capped(contact.phoneNumbers).omitted,
+            "emails": capped(contact.emailAddresses).omitted,
+            "addresses": capped(contact.postalAddresses).omitted,
+            "urls": capped(contact.urlAddresses).omitted,
+            "socialProfiles": capped(contact.socialProfiles).omitted,
+            "relations": capped(contact.contactRelations).omitted,
+        ].filter { $0.value > 0 }
+        if !omitted.isEmpty {
+            data["omitted"] = omitted
+        }
+        if let vcard {
+            data["vcard"] = vcard
+        }
+        return data
+    }
+
+    private func printJSON(_ contact: CNContact, vcard: String? = nil) {
+        let data = jsonObject(contact, vcard: vcard)
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: compactOption.writingOptions(.sortedKeys)),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+}
@@ -0,0 +1,41 @@
+import ArgumentParser
+import Foundation
+
+/// Filters on how many phones and emails a contact has (`list`, `search`)
+struct ValueCountOptions: ParsableArguments {
+    @Option(name: .long, help: "Only contacts with at least this many phone numbers")
+    var minPhones: Int?
+
+    @Option(name: .long, help: "Only contacts with at most this many phone numbers")
+    var maxPhones: Int?
+
+    @Option(name: .long, help: "Only contacts with at least this many email addresses")
+    var minEmails: Int?
+
+    @Option(name: .long, help: "Only contacts with at most this many email addresses")
+    var maxEmails: Int?
+
+    /// Whether any count filter is set
+    var isActive: Bool {
+        minPhones != nil || maxPhones != nil || minEmails != nil || maxEmails != nil
+    }
+
+    func matches(_ counts: ContactsService.ValueCounts) -> Bool {
+        counts.phones >= (minPhones ?? 0) && counts.phones <= (maxPhones ?? .max)
+            && counts.emails >= (minEmails ?? 0) && counts.emails <= (maxEmails ?? .max)
+    }
+
+    func validate() throws {
+        for (flag, value) in [("--min-phones", minPhones), ("--max-phones", maxPhones), ("--min-emails", minEmails), ("--max-emails", maxEmails)] {
+            if let value, value < 0 {
+                throw ValidationError("\(flag) cannot be negative")
+            }
+        }
+        if let minPhones, let maxPhones, minPhones > maxPhones {
+            throw ValidationError("--min-phones cannot be greater than --max-phones")
+        }
+        if let minEmails, let maxEmails, minEmails > maxEmails {
+            throw ValidationError("--min-emails cannot be greater than --max-emails")
+        }
+    }
+}
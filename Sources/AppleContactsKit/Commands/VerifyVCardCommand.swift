@@ -0,0 +1,87 @@
+import ArgumentParser
+import Foundation
+
+struct VerifyVCard: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "verify-vcard",
+        abstract: "Check a vCard file for structural problems",
+        discussion: """
+            Check that a .vcf file is well-formed before importing it elsewhere.
+            Reports cards without BEGIN/END:VCARD or VERSION, unknown ENCODING
+            values, invalid BDAY dates and lines that can't be parsed, each with
+            the line number it starts on. The file is also run through the
+            Contacts vCard parser. Missing FN is reported as a warning.
+
+            Only the file is read; Contacts access is not needed.
+
+            Exits with a non-zero status when errors are found.
+
+            Examples:
+              apple-contacts verify-vcard contacts.vcf
+              apple-contacts verify contacts.vcf --json
+            """,
+        aliases: ["verify"]
+    )
+
+    @Argument(help: "vCard file to check")
+    var file: String
+
+    @Flag(name: .shortAndLong, help: "Output as JSON")
+    var json = false
+
+    @OptionGroup var quietOption: QuietOption
+
+    func run() throws {
+        let data = try Data(contentsOf: URL(fileURLWithPath: file))
+        guard let text = String(data: data, encoding: .utf8) ?? String(data: data, encoding: .isoLatin1) else {
+            throw ContactsError.invalidVCard(file)
+        }
+
+        let issues = VCardValidator.validate(text)
+
+        if json {
+            printJSON(issues)
+        } else if !quietOption.quiet {
+            printTable(issues)
+        }
+
+        if issues.contains(where: { $0.severity == .error }) {
+            throw ExitCode.failure
+        }
+    }
+
+    private func printTable(_ issues: [VCardIssue]) {
+        if issues.isEmpty {
+            print("No problems found")
+            return
+        }
+
+        let lineWidth = max(4, issues.map { String($0.line).count }.max() ?? 4)
+
+        print("\("LINE".padding(toLength: lineWidth, withPad: " ", startingAt: 0))  SEVERITY  PROBLEM")
+        for issue in issues {
+            let line = (issue.line > 0 ? String(issue.line) : "-").padding(toLength: lineWidth, withPad: " ", startingAt: 0)
+            let severity = issue.severity.rawValue.padding(toLength: 8, withPad: " ", startingAt: 0)
+            print("\(line)  \(severity)  \(issue.message)")
+        }
+
+        let errors = issues.filter { $0.severity == .error }.count
+        print("\nFound \(errors) error(s), \(issues.count - errors) warning(s)")
+    }
+
+    private func printJSON(_ issues: [VCardIssue]) {
+        let data = issues.map { issue -> [String: Any] in
+            [
+                "line": issue.line,
+                "severity": issue.severity.rawValue,
+                "message": issue.message,
+            ]
+        }
+
+        if let jsonData = try? JSONSerialization.data(withJSONObject: data, options: .prettyPrinted),
+           let jsonString = String(data: jsonData, encoding: .utf8)
+        {
+            print(jsonString)
+        }
+    }
+}
@@ -0,0 +1,35 @@
+import ArgumentParser
+import Foundation
+
+/// Options for commands that add contacts (`create`, `import`). Unlike `GlobalOptions`
+/// there is nothing about how stored data is displayed, since these commands only
+/// print what they did.
+struct WriteOptions: ParsableArguments {
+    @OptionGroup var phoneRegionOption: PhoneRegionOption
+
+    @OptionGroup var idFormatOption: IDFormatOption
+
+    @OptionGroup var quietOption: QuietOption
+
+    @OptionGroup var logOptions: LogOptions
+
+    /// --quiet: skip human-readable output
+    var quiet: Bool {
+        quietOption.quiet
+    }
+
+    /// Region used to normalize phone numbers when matching duplicates
+    var resolvedPhoneRegion: String? {
+        phoneRegionOption.resolvedPhoneRegion
+    }
+
+    /// A contact ID as printed with --id-format
+    func displayID(_ id: String) -> String {
+        idFormatOption.displayID(id)
+    }
+
+    /// Logger for a command, configured from --verbose and --log-json
+    func logger(command: String) -> Logger {
+        logOptions.logger(command: command)
+    }
+}
@@ -5,13 +5,13 @@ enum EmbeddedSkill {
     static let skillMD = """
 ---
 name: apple-contacts
-description: Search and view Apple Contacts from the command line using apple-contacts CLI. Use when asked to search, list, show, or export contacts, find birthdays, browse contact groups, or look up people by name, email, phone, organization, or address. Read-only access using Apple's native Contacts Framework for fast, reliable lookups.
+description: Search and view Apple Contacts from the command line using apple-contacts CLI. Use when asked to search, list, show, or export contacts, find birthdays, browse contact groups, or look up people by name, email, phone, organization, or address, and create, edit, import or delete contacts. Uses Apple's native Contacts Framework for fast, reliable access. delete and prune --force remove contacts permanently.
 metadata: {"clawdbot":{"emoji":"📇","requires":{"bins":["apple-contacts"]},"install":[{"id":"source","kind":"source","repo":"https://github.com/fishfisher/apple-contacts","bins":["apple-contacts"],"label":"Install apple-contacts (from source)"}]}}
 ---
 
 # Apple Contacts
 
-Search and view your Apple Contacts from the command line using the `apple-contacts` CLI. This skill provides fast, read-only access to your contacts using Apple's native Contacts Framework.
+Search and view your Apple Contacts from the command line using the `apple-contacts` CLI. This skill provides fast access to your contacts using Apple's native Contacts Framework. Most commands only read; `create`, `edit`, `delete`, `import`, `prune --force` and `fix-encoding` change the address book.
 
 **Key Features:**
 - **Native framework**: Uses Apple's Contacts Framework for direct, fast access
@@ -284,7 +284,8 @@ done
 
 ## Limitations
 
-- **Read-only**: Cannot create, edit, or delete contacts (use Contacts.app for modifications)
+- **Modifying commands**: `create`, `edit`, `delete`, `import`, `prune --force` and `fix-encoding` change the address book; every other command only reads. `edit` only changes names, organization, job title, department, note and birthday (use Contacts.app for phones, emails and the rest)
+- **Destructive commands**: `delete` and `prune --force` remove contacts permanently, with no undo. Ask the user before running them, and run `prune` without `--force` first to see what would go
 - **macOS only**: Requires macOS 14.0 or later
 - **Notes field**: Accessing contact notes requires special Apple entitlements (not available)
 - **Permission required**: Must grant Contacts access on first run
@@ -0,0 +1,32 @@
+import ArgumentParser
+import Foundation
+
+/// Transliteration to plain ASCII for `export --ascii-only`, for older systems that
+/// can't read anything else: "Åse Ødegård" → "Ase Odegard", "Straße" → "Strasse",
+/// "北京" → "bei jing"
+enum ASCIIText {
+    /// What happens to characters with no ASCII form, such as emoji (--on-unmappable)
+    enum Unmappable: String, ExpressibleByArgument, CaseIterable {
+        /// Write "?" in their place
+        case replace
+        /// Leave them out
+        case drop
+    }
+
+    /// Other scripts are romanized first, then accents are removed and letters such as
+    /// ß, æ and ø are spelled out. ASCII text, including line breaks, is left as it is.
+    static func transliterate(_ text: String, unmappable: Unmappable = .replace) -> String {
+        let transform = StringTransform("Any-Latin; NFD; [:Nonspacing Mark:] Remove; NFC; Latin-ASCII")
+        let latin = text.applyingTransform(transform, reverse: false) ?? text
+
+        var result = String.UnicodeScalarView()
+        for scalar in latin.unicodeScalars {
+            if scalar.isASCII {
+                result.append(scalar)
+            } else if unmappable == .replace {
+                result.append("?")
+            }
+        }
+        return String(result)
+    }
+}
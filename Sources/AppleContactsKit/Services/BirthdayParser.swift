@@ -0,0 +1,108 @@
+import ArgumentParser
+import Foundation
+
+/// Parses birthdays given on the command line into date components for `CNContact.birthday`.
+/// Accepts "1985-03-14", "03-14" or "--03-14" (no year), "March 14", "14 March",
+/// "Mar 14, 1985" and "14 March 1985". The year is left out when not given, which is how
+/// Contacts stores year-less birthdays; vCards write those with Apple's 1604 placeholder
+/// year, so 1604 is read as "no year" too.
+enum BirthdayParser {
+    /// Apple's placeholder year for birthdays without one
+    static let omittedYear = 1604
+
+    static func parse(_ input: String) throws -> DateComponents {
+        let text = input.trimmingCharacters(in: .whitespaces)
+        guard let parsed = numeric(text) ?? named(text) else {
+            throw ValidationError("Invalid birthday '\(input)'. Use YYYY-MM-DD, MM-DD or a month name, e.g. \"March 14\"")
+        }
+        let (year, month, day) = parsed
+        let resolvedYear = year == omittedYear ? nil : year
+
+        guard isValid(year: resolvedYear, month: month, day: day) else {
+            throw ValidationError("Invalid birthday '\(input)': there is no such date")
+        }
+
+        var components = DateComponents()
+        components.year = resolvedYear
+        components.month = month
+        components.day = day
+        return components
+    }
+
+    /// YYYY-MM-DD, YYYYMMDD, MM-DD or --MM-DD
+    private static func numeric(_ text: String) -> (Int?, Int, Int)? {
+        let patterns: [(pattern: String, hasYear: Bool)] = [
+            (#"^(\d{4})-(\d{1,2})-(\d{1,2})$"#, true),
+            (#"^(\d{4})(\d{2})(\d{2})$"#, true),
+            (#"^(?:--)?(\d{1,2})-(\d{1,2})$"#, false),
+        ]
+        for (pattern, hasYear) in patterns {
+            guard let regex = try? NSRegularExpression(pattern: pattern),
+                  let match = regex.firstMatch(in: text, range: NSRange(text.startIndex..., in: text))
+            else {
+                continue
+            }
+            let numbers = (1..<match.numberOfRanges).compactMap { index in
+                Range(match.range(at: index), in: text).flatMap { Int(text[$0]) }
+            }
+            return hasYear ? (numbers[0], numbers[1], numbers[2]) : (nil, numbers[0], numbers[1])
+        }
+        return nil
+    }
+
+    /// A month name with a day and optional year, in either order: "March 14", "14 Mar 1985"
+    private static func named(_ text: String) -> (Int?, Int, Int)? {
+        let tokens = text.lowercased()
+            .split(whereSeparator: { !$0.isLetter && !$0.isNumber })
+            .map(String.init)
+
+        var month: Int?
+        var numbers: [Int] = []
+        for token in tokens {
+            if let number = Int(token) {
+                numbers.append(number)
+            } else if month == nil, let index = monthIndex(token) {
+                month = index
+            } else {
+                return nil
+            }
+        }
+
+        guard let month, let day = numbers.first, numbers.count <= 2 else {
+            return nil
+        }
+        let year = numbers.count == 2 ? numbers[1] : nil
+        if let year, year < 1000 {
+            return nil
+        }
+        return (year, month, day)
+    }
+
+    /// 1-based month for an English month name or its abbreviation ("mar", "sept")
+    private static func monthIndex(_ name: String) -> Int? {
+        let formatter = DateFormatter()
+        formatter.locale = Locale(identifier: "en_US_POSIX")
+        let full = formatter.monthSymbols.map { $0.lowercased() }
+        guard name.count >= 3, let index = full.firstIndex(where: { $0.hasPrefix(name) }) else {
+            return nil
+        }
+        return index + 1
+    }
+
+    /// Whether the date exists; February 29 is allowed without a year
+    private static func isValid(year: Int?, month: Int, day: Int) -> Bool {
+        guard (1...12).contains(month), day >= 1 else {
+            return false
+        }
+        var calendar = Calendar(identifier: .gregorian)
+        calendar.timeZone = TimeZone(identifier: "UTC")!
+        // 2000 is a leap year, so any day that exists in some year is accepted
+        let components = DateComponents(year: year ?? 2000, month: month)
+        guard let date = calendar.date(from: components),
+              let days = calendar.range(of: .day, in: .month, for: date)
+        else {
+            return false
+        }
+        return days.contains(day)
+    }
+}
@@ -0,0 +1,17 @@
+import Foundation
+
+/// Minimal RFC 4180 CSV encoding
+enum CSV {
+    /// Encode a single row, quoting fields that contain separators, quotes or newlines
+    static func row(_ fields: [String]) -> String {
+        fields.map(escape).joined(separator: ",")
+    }
+
+    /// Escape a single field
+    static func escape(_ field: String) -> String {
+        guard field.contains(where: { $0 == "," || $0 == "\"" || $0 == "\n" || $0 == "\r" }) else {
+            return field
+        }
+        return "\"" + field.replacingOccurrences(of: "\"", with: "\"\"") + "\""
+    }
+}
@@ -0,0 +1,58 @@
+import Foundation
+
+/// User configuration stored as JSON in `$XDG_CONFIG_HOME/apple-contacts/config.json`
+/// (default `~/.config/apple-contacts/config.json`)
+struct Config: Codable {
+    /// Saved searches: name to search arguments
+    var queries: [String: [String]] = [:]
+    /// Default phone region, used when --phone-region isn't given
+    var phoneRegion: String?
+    /// Preferred label order for --primary-first (e.g. ["work", "home"])
+    var labelOrder: [String]?
+
+    init() {}
+
+    init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        queries = try container.decodeIfPresent([String: [String]].self, forKey: .queries) ?? [:]
+        phoneRegion = try container.decodeIfPresent(String.self, forKey: .phoneRegion)
+        labelOrder = try container.decodeIfPresent([String].self, forKey: .labelOrder)
+    }
+
+    /// Location of the config file
+    static var fileURL: URL {
+        let base: URL
+        if let xdg = ProcessInfo.processInfo.environment["XDG_CONFIG_HOME"], !xdg.isEmpty {
+            base = URL(fileURLWithPath: xdg)
+        } else {
+            base = FileManager.default.homeDirectoryForCurrentUser.appendingPathComponent(".config")
+        }
+        return base.appendingPathComponent("apple-contacts").appendingPathComponent("config.json")
+    }
+
+    /// Load the config, or an empty one if the file doesn't exist
+    static func load() throws -> Config {
+        let url = fileURL
+        guard FileManager.default.fileExists(atPath: url.path) else {
+            return Config()
+        }
+        do {
+            return try JSONDecoder().decode(Config.self, from: Data(contentsOf: url))
+        } catch {
+            throw ContactsError.invalidConfig(url.path, reason: error.localizedDescription)
+        }
+    }
+
+    /// Write the config, creating its directory if needed
+    func save() throws {
+        let url = Self.fileURL
+        try FileManager.default.createDirectory(
+            at: url.deletingLastPathComponent(),
+            withIntermediateDirectories: true,
+            attributes: nil
+        )
+        let encoder = JSONEncoder()
+        encoder.outputFormatting = [.prettyPrinted, .sortedKeys]
+        try encoder.encode(self).write(to: url, options: .atomic)
+    }
+}
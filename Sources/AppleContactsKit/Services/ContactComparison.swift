@@ -0,0 +1,97 @@
+import Contacts
+import CryptoKit
+import Foundation
+
+/// One field of two contacts, for showing them side by side
+struct FieldDiff {
+    let field: String
+    let left: [String]
+    let right: [String]
+
+    /// Whether the two sides hold different values; multi-valued fields ignore order
+    var differs: Bool {
+        Set(left) != Set(right)
+    }
+}
+
+/// Compares two contacts field by field (`show --compare`), e.g. to decide which
+/// of two similar contacts to keep
+enum ContactComparison {
+    /// Keys for the fields beyond `ContactsService.fullKeys` that `compare` also looks at
+    /// when both contacts have them. The note needs the notes entitlement.
+    static var extraKeys: [CNKeyDescriptor] {
+        [
+            CNContactDatesKey as CNKeyDescriptor,
+            CNContactImageDataKey as CNKeyDescriptor,
+        ]
+    }
+
+    /// Fields set on either contact, in display order.
+    /// Both contacts must be fetched with `ContactsService.fullKeys`. The note, other dates
+    /// and photo are compared only when both contacts were fetched with their keys.
+    static func compare(_ a: CNContact, _ b: CNContact, redaction: Redaction = .none) -> [FieldDiff] {
+        zip(fields(a, redaction: redaction), fields(b, redaction: redaction)).compactMap { left, right in
+            guard let leftValues = left.values, let rightValues = right.values,
+                  !(leftValues.isEmpty && rightValues.isEmpty)
+            else {
+                return nil
+            }
+            return FieldDiff(field: left.name, left: leftValues, right: rightValues)
+        }
+    }
+
+    /// Names of the fields `compare` looks at for these two contacts, set or not
+    static func comparedFields(_ a: CNContact, _ b: CNContact) -> [String] {
+        zip(fields(a, redaction: .none), fields(b, redaction: .none))
+            .filter { $0.values != nil && $1.values != nil }
+            .map(\.0.name)
+    }
+
+    /// Each field's values, or nil when the contact wasn't fetched with the field's key
+    private static func fields(_ contact: CNContact, redaction: Redaction) -> [(name: String, values: [String]?)] {
+        func scalar(_ value: String) -> [String] {
+            value.isEmpty ? [] : [value]
+        }
+
+        func labeled<T: NSCopying & NSSecureCoding>(_ values: [CNLabeledValue<T>], _ text: (T) -> String) -> [String] {
+            values.map { "\(text($0.value)) (\(LabelOrder.displayLabel($0.label)))" }
+        }
+
+        func ifFetched(_ key: String, _ values: () -> [String]) -> [String]? {
+            contact.isKeyAvailable(key) ? values() : nil
+        }
+
+        return [
+            ("firstName", scalar(contact.givenName)),
+            ("middleName", scalar(contact.middleName)),
+            ("lastName", scalar(contact.familyName)),
+            ("nickname", scalar(contact.nickname)),
+            ("organization", scalar(contact.organizationName)),
+            ("department", scalar(contact.departmentName)),
+            ("jobTitle", scalar(contact.jobTitle)),
+            ("birthday", contact.birthdayString.map { [redaction.birthday($0)] } ?? []),
+            ("phones", labeled(contact.phoneNumbers) { redaction.phone($0.stringValue) }),
+            ("emails", labeled(contact.emailAddresses) { redaction.email($0 as String) }),
+            ("addresses", labeled(contact.postalAddresses) {
+                redaction.address($0).replacingOccurrences(of: "\n", with: ", ")
+            }),
+            ("urls", labeled(contact.urlAddresses) { $0 as String }),
+            ("socialProfiles", labeled(contact.socialProfiles) { "\($0.service): \($0.username.isEmpty ? $0.urlString : $0.username)" }),
+            ("instantMessages", labeled(contact.instantMessageAddresses) { "\($0.service): \($0.username)" }),
+            ("relations", labeled(contact.contactRelations) { $0.name }),
+            ("dates", ifFetched(CNContactDatesKey) {
+                labeled(contact.dates) { components in
+                    let date = components as DateComponents
+                    return [date.year, date.month, date.day].map { $0.map(String.init) ?? "-" }.joined(separator: "-")
+                }
+            }),
+            ("note", ifFetched(CNContactNoteKey) { scalar(contact.note) }),
+            // The photo is compared by a digest of its bytes
+            ("photo", ifFetched(CNContactImageDataKey) {
+                contact.imageData.map { data in
+                    ["\(data.count) bytes, sha256 " + SHA256.hash(data: data).prefix(8).map { String(format: "%02x", $0) }.joined()]
+                } ?? []
+            }),
+        ]
+    }
+}
@@ -0,0 +1,122 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+/// Extra data some fields need beyond the contact itself
+struct FieldContext {
+    /// Number of groups each contact belongs to, keyed by contact identifier.
+    /// Only populated when a selected field needs it.
+    var groupCounts: [String: Int] = [:]
+    /// Names of the groups each contact belongs to, keyed by contact identifier.
+    /// Only populated when a selected field needs it.
+    var groupNames: [String: [String]] = [:]
+    /// Names of the sources each contact is stored in, keyed by contact identifier.
+    /// Only populated when a selected field needs it.
+    var sourceNames: [String: [String]] = [:]
+    /// Masking for sensitive columns (--redact)
+    var redaction = Redaction.none
+    /// How the id column is printed (--id-format)
+    var idFormat = IDFormat.full
+    /// Locale that composes the name column (--locale); nil follows Contacts
+    var nameLocale: Locale?
+}
+
+/// A column that can be selected with `--fields`
+struct ContactField {
+    let name: String
+    let header: String
+    /// Keys to fetch in addition to `ContactsService.basicKeys`
+    let keys: [CNKeyDescriptor]
+    /// Whether group membership must be looked up for this field
+    let needsGroups: Bool
+    /// Whether the accounts contacts are stored in must be looked up for this field
+    let needsSources: Bool
+    let value: (CNContact, FieldContext) -> String
+
+    init(
+        _ name: String,
+        header: String,
+        keys: [CNKeyDescriptor] = [],
+        needsGroups: Bool = false,
+        needsSources: Bool = false,
+        value: @escaping (CNContact, FieldContext) -> String
+    ) {
+        self.name = name
+        self.header = header
+        self.keys = keys
+        self.needsGroups = needsGroups
+        self.needsSources = needsSources
+        self.value = value
+    }
+
+    /// All selectable fields
+    static var all: [ContactField] {
+        [
+            ContactField("id", header: "ID") { c, context in context.idFormat.apply(c.identifier) },
+            ContactField("name", header: "NAME") { c, context in c.fullName(locale: context.nameLocale) },
+            ContactField("firstName", header: "FIRST") { c, _ in c.givenName },
+            ContactField("lastName", header: "LAST") { c, _ in c.familyName },
+            ContactField("nickname", header: "NICKNAME") { c, _ in c.nickname },
+            ContactField("organization", header: "ORGANIZATION") { c, _ in c.organizationName },
+            ContactField("department", header: "DEPARTMENT", keys: [CNContactDepartmentNameKey as CNKeyDescriptor]) { c, _ in
+                c.departmentName
+            },
+            ContactField("jobTitle", header: "JOB TITLE", keys: [CNContactJobTitleKey as CNKeyDescriptor]) { c, _ in
+                c.jobTitle
+            },
+            ContactField("phone", header: "PHONE", keys: [CNContactPhoneNumbersKey as CNKeyDescriptor]) { c, context in
+                c.firstPhone.map(context.redaction.phone) ?? ""
+            },
+            ContactField("email", header: "EMAIL", keys: [CNContactEmailAddressesKey as CNKeyDescriptor]) { c, context in
+                c.firstEmail.map(context.redaction.email) ?? ""
+            },
+            ContactField("birthday", header: "BIRTHDAY", keys: [CNContactBirthdayKey as CNKeyDescriptor]) { c, context in
+                c.birthdayString.map(context.redaction.birthday) ?? ""
+            },
+
+            // Computed fields
+            ContactField("age", header: "AGE", keys: [CNContactBirthdayKey as CNKeyDescriptor]) { c, context in
+                c.age.map { context.redaction.birthday(String($0)) } ?? ""
+            },
+            ContactField("emailDomain", header: "EMAIL DOMAIN", keys: [CNContactEmailAddressesKey as CNKeyDescriptor]) { c, _ in
+                c.firstEmail.flatMap(EmailCase.domain) ?? ""
+            },
+            ContactField("groupCount", header: "GROUPS", needsGroups: true) { c, context in
+                String(context.groupCounts[c.identifier] ?? 0)
+            },
+            ContactField("groups", header: "GROUP NAMES", needsGroups: true) { c, context in
+                (context.groupNames[c.identifier] ?? []).joined(separator: ", ")
+            },
+            ContactField("source", header: "SOURCE", needsSources: true) { c, context in
+                ContactsService.sourceLabel(context.sourceNames[c.identifier])
+            },
+        ]
+    }
+
+    /// Parse a comma-separated field list, throwing on unknown names
+    static func parse(_ spec: String) throws -> [ContactField] {
+        let registry = all
+        return try spec.split(separator: ",").map { raw in
+            let name = raw.trimmingCharacters(in: .whitespaces)
+            guard let field = registry.first(where: { $0.name.lowercased() == name.lowercased() }) else {
+                let available = registry.map(\.name).joined(separator: ", ")
+                throw ContactsError.unknownField(name, available: available)
+            }
+            return field
+        }
+    }
+
+    /// Parse a field list from a file: one field per line, `#` starts a comment
+    static func parseFile(_ path: String) throws -> [ContactField] {
+        let text = try String(contentsOfFile: path, encoding: .utf8)
+        let names = text.components(separatedBy: .newlines).compactMap { line -> String? in
+            let name = line.split(separator: "#", maxSplits: 1, omittingEmptySubsequences: false)[0]
+                .trimmingCharacters(in: .whitespaces)
+            return name.isEmpty ? nil : name
+        }
+        guard !names.isEmpty else {
+            throw ValidationError("No fields listed in \(path)")
+        }
+        return try parse(names.joined(separator: ","))
+    }
+}
@@ -0,0 +1,133 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+/// Text with `{{field}}` placeholders filled in per contact (`list --template-file`).
+/// Placeholders take the same names as `--fields`, e.g. `{{name}} <{{email}}>`.
+struct ContactTemplate {
+    enum Segment {
+        case text(String)
+        case field(ContactField)
+    }
+
+    let segments: [Segment]
+
+    /// Fields used by the template, to know which keys to fetch
+    var fields: [ContactField] {
+        segments.compactMap { segment in
+            if case .field(let field) = segment { return field }
+            return nil
+        }
+    }
+
+    /// Parse a template, throwing on unknown fields or unclosed placeholders
+    init(parsing text: String) throws {
+        segments = try Self.tokenize(text).map { token in
+            switch token {
+            case .text(let text):
+                return .text(text)
+            case .placeholder(let name):
+                return .field(try ContactField.parse(name)[0])
+            }
+        }
+    }
+
+    func render(_ contact: CNContact, context: FieldContext) -> String {
+        segments.map { segment in
+            switch segment {
+            case .text(let text): return text
+            case .field(let field): return field.value(contact, context)
+            }
+        }.joined()
+    }
+
+    enum Token: Equatable {
+        case text(String)
+        case placeholder(String)
+    }
+
+    /// Split text into literal text and `{{name}}` placeholders (name trimmed)
+    static func tokenize(_ text: String) throws -> [Token] {
+        var tokens: [Token] = []
+        var rest = Substring(text)
+        while let open = rest.range(of: "{{") {
+            if open.lowerBound > rest.startIndex {
+                tokens.append(.text(String(rest[..<open.lowerBound])))
+            }
+            guard let close = rest[open.upperBound...].range(of: "}}") else {
+                throw ValidationError("Unclosed '{{' in template")
+            }
+            let name = rest[open.upperBound..<close.lowerBound].trimmingCharacters(in: .whitespaces)
+            guard !name.isEmpty else {
+                throw ValidationError("Empty '{{}}' in template")
+            }
+            tokens.append(.placeholder(name))
+            rest = rest[close.upperBound...]
+        }
+        if !rest.isEmpty {
+            tokens.append(.text(String(rest)))
+        }
+        return tokens
+    }
+}
+
+/// A template for the whole list (`list --list-template-file`): a header, a
+/// `{{range}}...{{end}}` block repeated for every contact, and a footer.
+/// The header and footer may use `{{count}}`, the number of contacts.
+struct ListTemplate {
+    let header: [ContactTemplate.Token]
+    let row: ContactTemplate
+    let footer: [ContactTemplate.Token]
+
+    /// A template that is just repeated per contact, each on its own line
+    init(row text: String) throws {
+        header = []
+        row = try ContactTemplate(parsing: text.hasSuffix("\n") ? text : text + "\n")
+        footer = []
+    }
+
+    init(parsing text: String) throws {
+        let tokens = try ContactTemplate.tokenize(text)
+        guard let start = tokens.firstIndex(of: .placeholder("range")),
+              let end = tokens[start...].firstIndex(of: .placeholder("end"))
+        else {
+            throw ValidationError("List template needs a {{range}} ... {{end}} block")
+        }
+
+        let body = tokens[(start + 1)..<end].map { token -> String in
+            switch token {
+            case .text(let text): return text
+            case .placeholder(let name): return "{{\(name)}}"
+            }
+        }.joined()
+
+        header = try Self.outerTokens(tokens[..<start])
+        row = try ContactTemplate(parsing: body)
+        footer = try Self.outerTokens(tokens[(end + 1)...])
+    }
+
+    /// Fields used by the row template
+    var fields: [ContactField] { row.fields }
+
+    func render(_ contacts: [CNContact], context: FieldContext) -> String {
+        let rows = contacts.map { row.render($0, context: context) }.joined()
+        return Self.fill(header, count: contacts.count) + rows + Self.fill(footer, count: contacts.count)
+    }
+
+    /// Header and footer only know about `{{count}}`
+    private static func outerTokens(_ tokens: ArraySlice<ContactTemplate.Token>) throws -> [ContactTemplate.Token] {
+        for case .placeholder(let name) in tokens where name != "count" {
+            throw ValidationError("'{{\(name)}}' can only be used inside {{range}} ... {{end}}; use {{count}} for the number of contacts")
+        }
+        return Array(tokens)
+    }
+
+    private static func fill(_ tokens: [ContactTemplate.Token], count: Int) -> String {
+        tokens.map { token in
+            switch token {
+            case .text(let text): return text
+            case .placeholder: return String(count)
+            }
+        }.joined()
+    }
+}
@@ -0,0 +1,1422 @@
+import Contacts
+import Foundation
+
+/// Service for interacting with Apple Contacts framework
+final class ContactsService {
+    private let store = CNContactStore()
+
+    /// When set, phones and emails of fetched and exported contacts are sorted by this label order
+    var labelOrder: [String]?
+
+    /// Trim and collapse whitespace in text fields of fetched contacts (in memory only)
+    var normalizeWhitespace = true
+
+    /// Sort phones, emails and addresses of fetched contacts by (label, value), so output
+    /// and content hashes don't change with the order Contacts happens to return them in
+    var stableOrder = true
+
+    /// Lowercase the domain of fetched and exported email addresses (in memory only)
+    var lowercaseEmails = false
+
+    /// With `lowercaseEmails`, lowercase the whole address rather than just the domain
+    var lowercaseWholeEmails = false
+
+    /// Return contacts exactly as stored: no whitespace trimming, email lowercasing, label
+    /// order or stable order. Those only change how contacts are shown, so every command
+    /// that saves or deletes the contacts it fetched calls this first; otherwise a save
+    /// would write the presentation changes back to the address book.
+    func useStoredValues() {
+        labelOrder = nil
+        normalizeWhitespace = false
+        stableOrder = false
+        lowercaseEmails = false
+        lowercaseWholeEmails = false
+    }
+
+    /// Locale used to order names, e.g. group names (--locale)
+    var locale = Locale.current
+
+    /// Sort listed contacts by name with `locale` instead of the Contacts sort order.
+    /// Set when --locale is given, so the system order stays the default.
+    var sortsByLocale = false
+
+    /// Locale whose conventions compose names, e.g. family name first in ja-JP. Only set
+    /// with --locale (`sortsByLocale`); nil follows the name order set in Contacts.
+    var nameLocale: Locale? {
+        sortsByLocale ? locale : nil
+    }
+
+    /// Told about each contact fetched by list and search, and serialized by the bulk vCard
+    /// exports (--progress-to)
+    var progress: ProgressSink?
+
+    /// Keys to fetch for basic contact info (fast)
+    static var basicKeys: [CNKeyDescriptor] {
+        [
+            CNContactIdentifierKey as CNKeyDescriptor,
+            CNContactGivenNameKey as CNKeyDescriptor,
+            CNContactFamilyNameKey as CNKeyDescriptor,
+            CNContactNicknameKey as CNKeyDescriptor,
+            CNContactOrganizationNameKey as CNKeyDescriptor,
+            CNContactTypeKey as CNKeyDescriptor,
+            CNContactFormatter.descriptorForRequiredKeys(for: .fullName),
+        ]
+    }
+
+    /// Keys to fetch for full contact info
+    /// Note: CNContactNoteKey is excluded because it requires special entitlements
+    static var fullKeys: [CNKeyDescriptor] {
+        [
+            CNContactIdentifierKey as CNKeyDescriptor,
+            CNContactGivenNameKey as CNKeyDescriptor,
+            CNContactFamilyNameKey as CNKeyDescriptor,
+            CNContactMiddleNameKey as CNKeyDescriptor,
+            CNContactPhoneticGivenNameKey as CNKeyDescriptor,
+            CNContactPhoneticMiddleNameKey as CNKeyDescriptor,
+            CNContactPhoneticFamilyNameKey as CNKeyDescriptor,
+            CNContactNicknameKey as CNKeyDescriptor,
+            CNContactOrganizationNameKey as CNKeyDescriptor,
+            CNContactTypeKey as CNKeyDescriptor,
+            CNContactDepartmentNameKey as CNKeyDescriptor,
+            CNContactJobTitleKey as CNKeyDescriptor,
+            CNContactBirthdayKey as CNKeyDescriptor,
+            CNContactPhoneNumbersKey as CNKeyDescriptor,
+            CNContactEmailAddressesKey as CNKeyDescriptor,
+            CNContactPostalAddressesKey as CNKeyDescriptor,
+            CNContactUrlAddressesKey as CNKeyDescriptor,
+            CNContactSocialProfilesKey as CNKeyDescriptor,
+            CNContactInstantMessageAddressesKey as CNKeyDescriptor,
+            CNContactRelationsKey as CNKeyDescriptor,
+            CNContactImageDataAvailableKey as CNKeyDescriptor,
+            CNContactFormatter.descriptorForRequiredKeys(for: .fullName),
+        ]
+    }
+
+    /// Keys needed for vCard export
+    static var vCardKeys: [CNKeyDescriptor] {
+        [CNContactVCardSerialization.descriptorForRequiredKeys()]
+    }
+
+    init() {}
+
+    /// Request access to contacts
+    func requestAccess() async throws -> Bool {
+        try await store.requestAccess(for: .contacts)
+    }
+
+    /// Check authorization status
+    var isAuthorized: Bool {
+        CNContactStore.authorizationStatus(for: .contacts) == .authorized
+    }
+
+    /// Ensure we have access, throw if not
+    func ensureAccess() throws {
+        let status = CNContactStore.authorizationStatus(for: .contacts)
+        switch status {
+        case .authorized:
+            return
+        case .notDetermined:
+            // For CLI, we can't easily request access synchronously
+            // User needs to grant permission via System Settings
+            throw ContactsError.accessDenied
+        case .denied, .restricted:
+            throw ContactsError.accessDenied
+        @unknown default:
+            throw ContactsError.accessDenied
+        }
+    }
+
+    // MARK: - Search Operations
+
+    /// Search contacts by name and, unless `includeNickname` is false, nickname
+    /// (fast - uses predicate for name). With `foldAccents`, accents and case are ignored
+    /// on both sides ("Jose" finds "José"), which means comparing every contact's name.
+    func searchByName(_ query: String, includeNickname: Bool = true, foldAccents: Bool = false) throws -> [CNContact] {
+        if foldAccents {
+            return normalized(try searchByNameFoldingAccents(query, includeNickname: includeNickname))
+        }
+
+        let predicate = CNContact.predicateForContacts(matchingName: query)
+        let byName = try store.unifiedContacts(matching: predicate, keysToFetch: Self.basicKeys)
+
+        // Also search by nickname (no built-in predicate, so fetch and filter)
+        let nicknameMatches = includeNickname ? try searchByNickname(query) : []
+
+        // Merge and deduplicate
+        var seen = Set<String>()
+        var results: [CNContact] = []
+
+        for contact in byName {
+            if !seen.contains(contact.identifier) {
+                seen.insert(contact.identifier)
+                results.append(contact)
+            }
+        }
+
+        for contact in nicknameMatches {
+            if !seen.contains(contact.identifier) {
+                seen.insert(contact.identifier)
+                results.append(contact)
+            }
+        }
+
+        return normalized(results)
+    }
+
+    /// Name search comparing accent-folded text (no predicate can do this)
+    private func searchByNameFoldingAccents(_ query: String, includeNickname: Bool) throws -> [CNContact] {
+        let folded = Self.foldAccents(query)
+        var results: [CNContact] = []
+
+        let request = CNContactFetchRequest(keysToFetch: Self.basicKeys)
+        try enumerate(request) { contact, _ in
+            var candidates = [contact.fullName, contact.givenName, contact.familyName]
+            if includeNickname {
+                candidates.append(contact.nickname)
+            }
+            if candidates.contains(where: { Self.foldAccents($0).contains(folded) }) {
+                results.append(contact)
+            }
+        }
+
+        return results
+    }
+
+    /// Lowercase text with diacritics removed ("José" -> "jose"), plus letters that
+    /// don't decompose into a base letter and a mark ("Søren" -> "soren")
+    static func foldAccents(_ text: String) -> String {
+        let replacements: [Character: String] = ["ø": "o", "æ": "ae", "œ": "oe", "ß": "ss", "ł": "l", "đ": "d"]
+        let folded = text.folding(options: [.diacriticInsensitive, .caseInsensitive], locale: nil)
+        return folded.reduce(into: "") { result, character in
+            if let replacement = replacements[character] {
+                result += replacement
+            } else {
+                result.append(character)
+            }
+        }
+    }
+
+    /// Search on just the first (given) or last (family) name, or the whole name with `exact`.
+    /// Candidates come from the name predicate, as in `searchContacts`, so a part matches where
+    /// one of its words starts with the query. Case-insensitive; with `foldAccents`, accents are
+    /// ignored too, which compares every contact since the predicate doesn't fold accents.
+    func searchByNamePart(
+        _ query: String,
+        part: KeyPath<CNContact, String>,
+        exact: Bool = false,
+        foldAccents: Bool = false
+    ) throws -> [CNContact] {
+        let query = query.trimmingCharacters(in: .whitespaces)
+        var results: [CNContact] = []
+
+        if foldAccents {
+            let request = CNContactFetchRequest(keysToFetch: Self.basicKeys)
+            try enumerate(request) { contact, _ in
+                if Self.namePartMatches(contact[keyPath: part], query: query, exact: exact, foldAccents: true) {
+                    results.append(contact)
+                }
+            }
+        } else {
+            let predicate = CNContact.predicateForContacts(matchingName: query)
+            results = try store.unifiedContacts(matching: predicate, keysToFetch: Self.basicKeys).filter {
+                Self.namePartMatches($0[keyPath: part], query: query, exact: exact)
+            }
+        }
+
+        return normalized(results)
+    }
+
+    /// Whether a first or last name matches `searchByNamePart`'s query: contains it,
+    /// or equals it with `exact`
+    static func namePartMatches(_ value: String, query: String, exact: Bool, foldAccents: Bool = false) -> Bool {
+        let fold: (String) -> String = foldAccents ? Self.foldAccents : { $0.lowercased() }
+        let value = fold(value.trimmingCharacters(in: .whitespaces))
+        let query = fold(query.trimmingCharacters(in: .whitespaces))
+        return exact ? value == query : value.contains(query)
+    }
+
+    /// Search contacts by nickname
+    private func searchByNickname(_ query: String) throws -> [CNContact] {
+        let queryLower = query.lowercased()
+        var results: [CNContact] = []
+
+        let request = CNContactFetchRequest(keysToFetch: Self.basicKeys)
+        try enumerate(request) { contact, _ in
+            if contact.nickname.lowercased().contains(queryLower) {
+                results.append(contact)
+            }
+        }
+
+        return results
+    }
+
+    /// Search contacts by name and, unless `includeNickname` is false, nickname, tolerating typos.
+    /// Returns matches with their edit distance, best first.
+    func searchFuzzy(_ query: String, includeNickname: Bool = true) throws -> [(contact: CNContact, score: Int)] {
+        let maxDistance = Fuzzy.maxDistance(for: query)
+        var results: [(contact: CNContact, score: Int)] = []
+
+        let request = CNContactFetchRequest(keysToFetch: Self.basicKeys)
+        try enumerate(request) { contact, _ in
+            var candidates = [contact.fullName, contact.givenName, contact.familyName]
+            if includeNickname {
+                candidates.append(contact.nickname)
+            }
+            if let score = Fuzzy.score(query, against: candidates), score <= maxDistance {
+                results.append((contact, score))
+            }
+        }
+
+        return results
+            .sorted { $0.score < $1.score }
+            .map { (normalized($0.contact), $0.score) }
+    }
+
+    /// Search contacts by email
+    func searchByEmail(_ query: String) throws -> [CNContact] {
+        // Try the indexed predicate first (exact address). Partial terms like "@company.com"
+        // can never match it, so only complete addresses take the fast path.
+        if Self.isCompleteEmail(query) {
+            let predicate = CNContact.predicateForContacts(matchingEmailAddress: query)
+            if let contacts = try? store.unifiedContacts(matching: predicate, keysToFetch: Self.basicKeys),
+               !contacts.isEmpty
+            {
+                return normalized(contacts)
+            }
+        }
+
+        // Fall back to contains search
+        let queryLower = query.lowercased()
+        var results: [CNContact] = []
+
+        let keys = Self.basicKeys + [CNContactEmailAddressesKey as CNKeyDescriptor]
+        let request = CNContactFetchRequest(keysToFetch: keys)
+        try enumerate(request) { contact, _ in
+            for email in contact.emailAddresses {
+                if (email.value as String).lowercased().contains(queryLower) {
+                    results.append(contact)
+                    break
+                }
+            }
+        }
+
+        return normalized(results)
+    }
+
+    /// Whether a search term is a whole address ("name@example.com") rather than a fragment
+    private static func isCompleteEmail(_ query: String) -> Bool {
+        let parts = query.split(separator: "@", omittingEmptySubsequences: false)
+        guard parts.count == 2, !parts[0].isEmpty, !query.contains(" ") else {
+            return false
+        }
+        let domain = parts[1]
+        return domain.contains(".") && !domain.hasPrefix(".") && !domain.hasSuffix(".")
+    }
+
+    /// Search contacts by phone number
+    /// The region expands national numbers so "900 00 000" matches "+47 900 00 000" in NO.
+    /// With `exact`, a stored number must equal the query once both are normalized,
+    /// so "+47 900 00 000" and "0047 90000000" match but "+47 900" doesn't.
+    func searchByPhone(_ query: String, region: String? = nil, exact: Bool = false) throws -> [CNContact] {
+        // Normalize query - keep only digits and +
+        let normalizedQuery = PhoneNumbers.digits(query)
+        let internationalQuery = PhoneNumbers.normalize(query, region: region)
+
+        // Try predicate match first; it matches loosely, so not for exact searches
+        if !exact {
+            let phoneNumber = CNPhoneNumber(stringValue: query)
+            let predicate = CNContact.predicateForContacts(matching: phoneNumber)
+            if let contacts = try? store.unifiedContacts(matching: predicate, keysToFetch: Self.basicKeys),
+               !contacts.isEmpty
+            {
+                return normalized(contacts)
+            }
+        }
+
+        // Fall back to contains search
+        var results: [CNContact] = []
+
+        let keys = Self.basicKeys + [CNContactPhoneNumbersKey as CNKeyDescriptor]
+        let request = CNContactFetchRequest(keysToFetch: keys)
+        try enumerate(request) { contact, _ in
+            for phone in contact.phoneNumbers {
+                let phoneDigits = PhoneNumbers.digits(phone.value.stringValue)
+                let international = PhoneNumbers.normalize(phone.value.stringValue, region: region)
+                let matches = exact
+                    ? !internationalQuery.isEmpty && international == internationalQuery
+                    : phoneDigits.contains(normalizedQuery) || international.contains(internationalQuery)
+                if matches {
+                    results.append(contact)
+                    break
+                }
+            }
+        }
+
+        return normalized(results)
+    }
+
+    /// Search contacts by organization
+    /// With `exact`, the whole organization name must match (case-insensitive, ignoring
+    /// surrounding whitespace), so "Acme" doesn't match "Acme Holdings".
+    /// With `foldAccents`, accents are ignored too ("Cafe" matches "Café").
+    func searchByOrganization(_ query: String, exact: Bool = false, foldAccents: Bool = false) throws -> [CNContact] {
+        var results: [CNContact] = []
+
+        let request = CNContactFetchRequest(keysToFetch: Self.basicKeys)
+        try enumerate(request) { contact, _ in
+            if Self.fieldMatches(contact.organizationName, query: query, exact: exact, foldAccents: foldAccents) {
+                results.append(contact)
+            }
+        }
+
+        return normalized(results)
+    }
+
+    /// Search contacts by department (contains, or the whole department with `exact`)
+    func searchByDepartment(_ query: String, exact: Bool = false, foldAccents: Bool = false) throws -> [CNContact] {
+        var results: [CNContact] = []
+
+        let request = CNContactFetchRequest(keysToFetch: Self.basicKeys + [CNContactDepartmentNameKey as CNKeyDescriptor])
+        try enumerate(request) { contact, _ in
+            if Self.fieldMatches(contact.departmentName, query: query, exact: exact, foldAccents: foldAccents) {
+                results.append(contact)
+            }
+        }
+
+        return normalized(results)
+    }
+
+    /// Whether an organization or department matches a query: contains it, or with `exact`
+    /// equals it ignoring surrounding whitespace. Case is always ignored, accents with `foldAccents`.
+    static func fieldMatches(_ value: String, query: String, exact: Bool, foldAccents: Bool) -> Bool {
+        let fold: (String) -> String = foldAccents ? Self.foldAccents : { $0.lowercased() }
+        let folded = fold(value)
+        if exact {
+            return folded.trimmingCharacters(in: .whitespaces) == fold(query).trimmingCharacters(in: .whitespaces)
+        }
+        return folded.contains(fold(query))
+    }
+
+    /// Number of phones and emails on a contact
+    struct ValueCounts {
+        let phones: Int
+        let emails: Int
+    }
+
+    /// Phone and email counts of every contact, keyed by identifier
+    func valueCounts() throws -> [String: ValueCounts] {
+        var counts: [String: ValueCounts] = [:]
+
+        let keys = [
+            CNContactIdentifierKey as CNKeyDescriptor,
+            CNContactPhoneNumbersKey as CNKeyDescriptor,
+            CNContactEmailAddressesKey as CNKeyDescriptor,
+        ]
+        let request = CNContactFetchRequest(keysToFetch: keys)
+        try store.enumerateContacts(with: request) { contact, _ in
+            counts[contact.identifier] = ValueCounts(phones: contact.phoneNumbers.count, emails: contact.emailAddresses.count)
+        }
+
+        return counts
+    }
+
+    /// Full names (lowercased) that more than one contact has, with how many contacts share
+    /// each. Contacts without a name are left out.
+    func duplicateNames() throws -> [String: Int] {
+        var counts: [String: Int] = [:]
+        for contact in try listContacts() {
+            let name = contact.fullName.lowercased()
+            if !name.isEmpty {
+                counts[name, default: 0] += 1
+            }
+        }
+        return counts.filter { $0.value > 1 }
+    }
+
+    /// Search contacts by address. `query` matches anywhere in the formatted address, `city`
+    /// and `postalCode` in those parts (contains), and `label` the address label (e.g. "home",
+    /// or a custom label). All given conditions must hold for the same address.
+    func searchByAddress(
+        _ query: String? = nil,
+        label: String? = nil,
+        city: String? = nil,
+        postalCode: String? = nil
+    ) throws -> [CNContact] {
+        var results: [CNContact] = []
+
+        let keys = Self.basicKeys + [CNContactPostalAddressesKey as CNKeyDescriptor]
+        let request = CNContactFetchRequest(keysToFetch: keys)
+        try enumerate(request) { contact, _ in
+            if Self.hasAddress(contact, matching: query, label: label, city: city, postalCode: postalCode) {
+                results.append(contact)
+            }
+        }
+
+        return normalized(results)
+    }
+
+    /// Whether one of the contact's addresses meets every given condition of `searchByAddress`
+    static func hasAddress(
+        _ contact: CNContact,
+        matching query: String? = nil,
+        label: String? = nil,
+        city: String? = nil,
+        postalCode: String? = nil
+    ) -> Bool {
+        let queryLower = query?.lowercased()
+        let labelLower = label?.lowercased()
+        let cityLower = city?.lowercased()
+        let postalCodeCompact = postalCode.map { $0.lowercased().filter { !$0.isWhitespace } }
+
+        func matches(_ address: CNLabeledValue<CNPostalAddress>) -> Bool {
+            if let labelLower, LabelOrder.cleanLabel(address.label) != labelLower {
+                return false
+            }
+            if let cityLower, !address.value.city.lowercased().contains(cityLower) {
+                return false
+            }
+            if let postalCodeCompact,
+               !address.value.postalCode.lowercased().filter({ !$0.isWhitespace }).contains(postalCodeCompact)
+            {
+                return false
+            }
+            if let queryLower {
+                let formatted = CNPostalAddressFormatter.string(from: address.value, style: .mailingAddress)
+                return formatted.lowercased().contains(queryLower)
+            }
+            return true
+        }
+
+        return contact.postalAddresses.contains(where: matches)
+    }
+
+    /// Search contacts by whether they have a note
+    /// Note: reading notes requires the Contacts notes entitlement
+    func searchByNote(present: Bool) throws -> [CNContact] {
+        var results: [CNContact] = []
+
+        let keys = Self.basicKeys + [CNContactNoteKey as CNKeyDescriptor]
+        let request = CNContactFetchRequest(keysToFetch: keys)
+        do {
+            try enumerate(request) { contact, _ in
+                let hasNote = !contact.note.trimmingCharacters(in: .whitespacesAndNewlines).isEmpty
+                if hasNote == present {
+                    results.append(contact)
+                }
+            }
+        } catch {
+            throw ContactsError.notesUnavailable
+        }
+
+        return normalized(results)
+    }
+
+    /// `key: value` fields parsed from each contact's note, keyed by contact identifier.
+    /// Contacts without any are left out.
+    /// Note: reading notes requires the Contacts notes entitlement
+    func noteFields() throws -> [String: [String: String]] {
+        var results: [String: [String: String]] = [:]
+
+        let keys = [CNContactIdentifierKey as CNKeyDescriptor, CNContactNoteKey as CNKeyDescriptor]
+        let request = CNContactFetchRequest(keysToFetch: keys)
+        do {
+            try store.enumerateContacts(with: request) { contact, _ in
+                let fields = NoteFields.parse(contact.note)
+                if !fields.isEmpty {
+                    results[contact.identifier] = fields
+                }
+            }
+        } catch {
+            throw ContactsError.notesUnavailable
+        }
+
+        return results
+    }
+
+    /// Search contacts by birthday
+    func searchByBirthday(month: Int?, day: Int?) throws -> [CNContact] {
+        var results: [CNContact] = []
+
+        let keys = Self.basicKeys + [CNContactBirthdayKey as CNKeyDescriptor]
+        let request = CNContactFetchRequest(keysToFetch: keys)
+        try enumerate(request) { contact, _ in
+            guard let birthday = contact.birthday else { return }
+
+            var matches = true
+            if let m = month, birthday.month != m {
+                matches = false
+            }
+            if let d = day, birthday.day != d {
+                matches = false
+            }
+
+            if matches && (month != nil || day != nil) {
+                results.append(contact)
+            }
+        }
+
+        return normalized(results)
+    }
+
+    /// Search across all fields
+    func searchAll(_ query: String) throws -> [CNContact] {
+        let queryLower = query.lowercased()
+        var seen = Set<String>()
+        var results: [CNContact] = []
+
+        // First do fast name search
+        let byName = try searchByName(query)
+        for contact in byName {
+            if !seen.contains(contact.identifier) {
+                seen.insert(contact.identifier)
+                results.append(contact)
+            }
+        }
+
+        // Then search other fields
+        let keys: [CNKeyDescriptor] = Self.basicKeys + [
+            CNContactPhoneNumbersKey as CNKeyDescriptor,
+            CNContactEmailAddressesKey as CNKeyDescriptor,
+            CNContactPostalAddressesKey as CNKeyDescriptor,
+        ]
+
+        let request = CNContactFetchRequest(keysToFetch: keys)
+        try enumerate(request) { contact, _ in
+            if seen.contains(contact.identifier) { return }
+
+            // Check organization
+            if contact.organizationName.lowercased().contains(queryLower) {
+                seen.insert(contact.identifier)
+                results.append(contact)
+                return
+            }
+
+            // Check emails
+            for email in contact.emailAddresses {
+                if (email.value as String).lowercased().contains(queryLower) {
+                    seen.insert(contact.identifier)
+                    results.append(contact)
+                    return
+                }
+            }
+
+            // Check phones
+            for phone in contact.phoneNumbers {
+                if phone.value.stringValue.contains(query) {
+                    seen.insert(contact.identifier)
+                    results.append(contact)
+                    return
+                }
+            }
+
+            // Check addresses
+            for address in contact.postalAddresses {
+                let formatted = CNPostalAddressFormatter.string(from: address.value, style: .mailingAddress)
+                if formatted.lowercased().contains(queryLower) {
+                    seen.insert(contact.identifier)
+                    results.append(contact)
+                    return
+                }
+            }
+        }
+
+        return normalized(results)
+    }
+
+    // MARK: - Get Operations
+
+    /// Fetch contacts by identifier with the given keys, in the order of `ids`
+    func getContacts(ids: [String], keysToFetch keys: [CNKeyDescriptor]) throws -> [CNContact] {
+        let predicate = CNContact.predicateForContacts(withIdentifiers: ids)
+        let byID = Dictionary(
+            try store.unifiedContacts(matching: predicate, keysToFetch: keys).map { ($0.identifier, $0) },
+            uniquingKeysWith: { first, _ in first }
+        )
+        return normalized(ids.compactMap { byID[$0] })
+    }
+
+    /// Get a contact by identifier, falling back to a unique identifier prefix
+    /// (e.g. "ABC123-DEF456" for "ABC123-DEF456:ABPerson")
+    func getContact(id: String) throws -> CNContact? {
+        let predicate = CNContact.predicateForContacts(withIdentifiers: [id])
+        if let contact = try store.unifiedContacts(matching: predicate, keysToFetch: Self.fullKeys).first {
+            return applyingLabelOrder(contact)
+        }
+
+        guard let resolved = try resolveIDPrefix(id) else {
+            return nil
+        }
+        let resolvedPredicate = CNContact.predicateForContacts(withIdentifiers: [resolved])
+        return try store.unifiedContacts(matching: resolvedPredicate, keysToFetch: Self.fullKeys)
+            .first.map(applyingLabelOrder)
+    }
+
+    /// Find the single identifier starting with a prefix; throws if several match
+    func resolveIDPrefix(_ prefix: String) throws -> String? {
+        guard !prefix.isEmpty else { return nil }
+
+        var ids: [String] = []
+        let request = CNContactFetchRequest(keysToFetch: [CNContactIdentifierKey as CNKeyDescriptor])
+        try store.enumerateContacts(with: request) { contact, _ in
+            ids.append(contact.identifier)
+        }
+        return try Self.matchIDPrefix(prefix, in: ids)
+    }
+
+    /// The identifier in `ids` equal to `prefix`, else the only one starting with it.
+    /// Nil when none match; throws `ambiguousID` when several start with it.
+    static func matchIDPrefix(_ prefix: String, in ids: [String]) throws -> String? {
+        guard !prefix.isEmpty else { return nil }
+        if ids.contains(prefix) {
+            return prefix
+        }
+
+        let matches = ids.filter { $0.hasPrefix(prefix) }
+        if matches.count > 1 {
+            throw ContactsError.ambiguousID(prefix, matches: matches.count)
+        }
+        return matches.first
+    }
+
+    /// Get a contact by name (returns first match, prefers exact)
+    func getContact(name: String) throws -> CNContact? {
+        let predicate = CNContact.predicateForContacts(matchingName: name)
+        let contacts = try store.unifiedContacts(matching: predicate, keysToFetch: Self.fullKeys)
+
+        // Prefer exact match
+        let nameLower = name.lowercased()
+        for contact in contacts {
+            let fullName = contact.fullName.lowercased()
+            if fullName == nameLower {
+                return applyingLabelOrder(contact)
+            }
+        }
+
+        return contacts.first.map(applyingLabelOrder)
+    }
+
+    /// Get the only contact matching a name, for commands that modify it.
+    /// A single exact full-name match wins over partial matches; otherwise more than
+    /// one match throws `ambiguousName`.
+    func getUniqueContact(name: String) throws -> CNContact? {
+        let predicate = CNContact.predicateForContacts(matchingName: name)
+        let contacts = try store.unifiedContacts(matching: predicate, keysToFetch: Self.fullKeys)
+
+        let nameLower = name.lowercased()
+        let exact = contacts.filter { $0.fullName.lowercased() == nameLower }
+        let candidates = exact.count == 1 ? exact : contacts
+        if candidates.count > 1 {
+            throw ContactsError.ambiguousName(name, matches: candidates.count)
+        }
+        return candidates.first.map(applyingLabelOrder)
+    }
+
+    /// Get the first contact matching a name, without preferring an exact match.
+    /// Stops at the first hit instead of fetching every match.
+    func getFirstContact(name: String) throws -> CNContact? {
+        let request = CNContactFetchRequest(keysToFetch: Self.fullKeys)
+        request.predicate = CNContact.predicateForContacts(matchingName: name)
+        request.unifyResults = true
+
+        var first: CNContact?
+        try store.enumerateContacts(with: request) { contact, stop in
+            first = contact
+            stop.pointee = true
+        }
+        return first.map(applyingLabelOrder)
+    }
+
+    /// Sort phones and emails by `labelOrder`, if set
+    private func applyingLabelOrder(_ contact: CNContact) -> CNContact {
+        guard let labelOrder else { return normalized(contact) }
+        return normalized(contact).withLabelOrder(labelOrder)
+    }
+
+    /// Apply whitespace normalization, email lowercasing and stable ordering, if enabled
+    private func normalized(_ contact: CNContact) -> CNContact {
+        var result = normalizeWhitespace ? Self.trimContact(contact) : contact
+        if lowercaseEmails {
+            result = EmailCase.normalizing(result, wholeAddress: lowercaseWholeEmails)
+        }
+        return stableOrder ? result.withStableOrder() : result
+    }
+
+    /// Apply whitespace normalization, email lowercasing and stable ordering to a list, if enabled
+    private func normalized(_ contacts: [CNContact]) -> [CNContact] {
+        contacts.map(normalized)
+    }
+
+    /// Copy of a contact with leading/trailing whitespace trimmed and internal runs of
+    /// whitespace collapsed in its text fields. Returns the contact itself if nothing changes.
+    static func trimContact(_ contact: CNContact) -> CNContact {
+        let fields = CNContact.textFields.filter { contact.isKeyAvailable($0.key) }
+        let changes = fields.compactMap { field -> (ReferenceWritableKeyPath<CNMutableContact, String>, String)? in
+            let value = contact[keyPath: field.read]
+            let trimmed = value.split(whereSeparator: \.isWhitespace).joined(separator: " ")
+            return trimmed == value ? nil : (field.write, trimmed)
+        }
+        guard !changes.isEmpty else { return contact }
+
+        let mutable = contact.mutableCopy() as! CNMutableContact
+        for (keyPath, value) in changes {
+            mutable[keyPath: keyPath] = value
+        }
+        return mutable.copy() as! CNContact
+    }
+
+    // MARK: - List Operations
+
+    /// List all contacts
+    func listContacts(limit: Int? = nil, extraKeys: [CNKeyDescriptor] = []) throws -> [CNContact] {
+        var results: [CNContact] = []
+
+        let request = CNContactFetchRequest(keysToFetch: Self.basicKeys + extraKeys)
+        request.sortOrder = .userDefault
+
+        // Collating needs every contact before the limit can be applied
+        try enumerate(request, limit: sortsByLocale ? nil : limit) { contact, stop in
+            results.append(contact)
+            if let limit, results.count >= limit, !sortsByLocale {
+                stop.pointee = true
+            }
+        }
+
+        guard sortsByLocale else {
+            return normalized(results)
+        }
+        let sorted = sortedByName(results)
+        return normalized(limit.map { Array(sorted.prefix($0)) } ?? sorted)
+    }
+
+    /// Contacts ordered by display name with `locale`'s collation (e.g. `Å` after `Z` in nb-NO)
+    func sortedByName(_ contacts: [CNContact]) -> [CNContact] {
+        contacts
+            .map { (contact: $0, name: $0.displayName(locale: nameLocale)) }
+            .sorted { $0.name.compare($1.name, options: [.caseInsensitive, .numeric], range: nil, locale: locale) == .orderedAscending }
+            .map(\.contact)
+    }
+
+    /// Fetch every contact with the given keys, normalized like every other read
+    func fetchAll(keysToFetch keys: [CNKeyDescriptor]) throws -> [CNContact] {
+        var results: [CNContact] = []
+
+        let request = CNContactFetchRequest(keysToFetch: keys)
+        request.sortOrder = .userDefault
+
+        try store.enumerateContacts(with: request) { contact, _ in
+            results.append(contact)
+        }
+
+        return normalized(results)
+    }
+
+    /// List all groups
+    func listGroups() throws -> [CNGroup] {
+        try store.groups(matching: nil)
+    }
+
+    /// List contacts in a group
+    func listContactsInGroup(_ group: CNGroup, extraKeys: [CNKeyDescriptor] = []) throws -> [CNContact] {
+        let predicate = CNContact.predicateForContactsInGroup(withIdentifier: group.identifier)
+        let members = try store.unifiedContacts(matching: predicate, keysToFetch: Self.basicKeys + extraKeys)
+        return normalized(sortsByLocale ? sortedByName(members) : members)
+    }
+
+    /// Get the identifiers of every member of each group, keyed by group identifier
+    /// With `concurrency` above 1, that many groups are fetched at a time.
+    func memberIDs(of groups: [CNGroup], concurrency: Int = 1) throws -> [String: Set<String>] {
+        let members = try Parallel.map(groups, concurrency: concurrency) { try memberIDs(of: $0) }
+        return Dictionary(zip(groups.map(\.identifier), members), uniquingKeysWith: { first, _ in first })
+    }
+
+    /// Identifiers of the members of one group
+    func memberIDs(of group: CNGroup) throws -> Set<String> {
+        let predicate = CNContact.predicateForContactsInGroup(withIdentifier: group.identifier)
+        let members = try store.unifiedContacts(
+            matching: predicate,
+            keysToFetch: [CNContactIdentifierKey as CNKeyDescriptor]
+        )
+        return Set(members.map(\.identifier))
+    }
+
+    /// Names of the groups each contact belongs to, in alphabetical order, keyed by
+    /// contact identifier. Each group's members are fetched once.
+    func groupNames() throws -> [String: [String]] {
+        let groups = try listGroups().sorted {
+            $0.name.compare($1.name, options: [.caseInsensitive, .numeric], range: nil, locale: locale) == .orderedAscending
+        }
+        let members = try memberIDs(of: groups)
+
+        var names: [String: [String]] = [:]
+        for group in groups {
+            for id in members[group.identifier] ?? [] {
+                names[id, default: []].append(group.name)
+            }
+        }
+        return names
+    }
+
+    /// Get group by name
+    func getGroup(name: String) throws -> CNGroup? {
+        let groups = try listGroups()
+        return groups.first { $0.name == name }
+    }
+
+    /// Identifiers of everyone in any of the named groups, for excluding them from results.
+    /// Throws `unknownGroup` for a name that matches no group.
+    func memberIDs(ofGroupsNamed names: [String]) throws -> Set<String> {
+        let groups = try listGroups()
+        let matched = try names.map { name -> CNGroup in
+            guard let group = groups.first(where: { $0.name == name }) else {
+                throw ContactsError.unknownGroup(name)
+            }
+            return group
+        }
+        return try memberIDs(of: matched).values.reduce(into: Set<String>()) { $0.formUnion($1) }
+    }
+
+    // MARK: - Sources
+
+    /// Accounts (containers) contacts are stored in, e.g. iCloud or On My Mac
+    func listSources() throws -> [CNContainer] {
+        try store.containers(matching: nil)
+    }
+
+    /// Identifiers of every contact stored in any of the named sources (case-insensitive).
+    /// A contact linked across accounts counts as in the source when any of its cards is.
+    /// Throws `unknownSource` for a name that matches no source.
+    func memberIDs(ofSourcesNamed names: [String]) throws -> Set<String> {
+        let sources = try listSources()
+        var ids = Set<String>()
+        for name in names {
+            guard let source = sources.first(where: { $0.displayName.caseInsensitiveCompare(name) == .orderedSame }) else {
+                let available = sources.map(\.displayName).sorted().map { "'\($0)'" }.joined(separator: ", ")
+                throw ContactsError.unknownSource(name, available: available)
+            }
+            let predicate = CNContact.predicateForContactsInContainer(withIdentifier: source.identifier)
+            let members = try store.unifiedContacts(
+                matching: predicate,
+                keysToFetch: [CNContactIdentifierKey as CNKeyDescriptor]
+            )
+            ids.formUnion(members.map(\.identifier))
+        }
+        return ids
+    }
+
+    /// Names of the sources each contact is stored in, keyed by identifier and sorted.
+    /// A contact linked across accounts lists every source one of its cards is in.
+    func sourceNames() throws -> [String: [String]] {
+        let sources = try listSources().sorted {
+            $0.displayName.compare($1.displayName, options: [.caseInsensitive, .numeric], range: nil, locale: locale) == .orderedAscending
+        }
+        var names: [String: [String]] = [:]
+        for source in sources {
+            let predicate = CNContact.predicateForContactsInContainer(withIdentifier: source.identifier)
+            let members = try store.unifiedContacts(
+                matching: predicate,
+                keysToFetch: [CNContactIdentifierKey as CNKeyDescriptor]
+            )
+            for member in members where !(names[member.identifier] ?? []).contains(source.displayName) {
+                names[member.identifier, default: []].append(source.displayName)
+            }
+        }
+        return names
+    }
+
+    /// A contact's sources as shown in a SOURCE column, or "(unknown)" when the contact
+    /// wasn't found in any source
+    static func sourceLabel(_ names: [String]?) -> String {
+        guard let names, !names.isEmpty else {
+            return "(unknown)"
+        }
+        return names.joined(separator: ", ")
+    }
+
+    // MARK: - Write Operations
+
+    /// Save changes to an existing contact
+    /// The contact must have been fetched with every key that was modified
+    func updateContact(_ contact: CNMutableContact) throws {
+        let request = CNSaveRequest()
+        request.update(contact)
+        try store.execute(request)
+    }
+
+    /// Save a new contact to the default container
+    func addContact(_ contact: CNMutableContact) throws {
+        let request = CNSaveRequest()
+        request.add(contact, toContainerWithIdentifier: nil)
+        try store.execute(request)
+    }
+
+    /// Delete a single contact
+    func deleteContact(_ contact: CNContact) throws {
+        try deleteContacts([contact])
+    }
+
+    /// Delete contacts
+    func deleteContacts(_ contacts: [CNContact]) throws {
+        let request = CNSaveRequest()
+        for contact in contacts {
+            request.delete(contact.mutableCopy() as! CNMutableContact)
+        }
+        try store.execute(request)
+    }
+
+    // MARK: - Export Operations
+
+    /// Export contact as vCard data
+    func exportVCard(contact: CNContact) throws -> Data {
+        // Refetch with vCard keys
+        let predicate = CNContact.predicateForContacts(withIdentifiers: [contact.identifier])
+        let contacts = try store.unifiedContacts(matching: predicate, keysToFetch: Self.vCardKeys)
+        guard let fullContact = contacts.first else {
+            throw ContactsError.contactNotFound
+        }
+        return try CNContactVCardSerialization.data(with: [applyingLabelOrder(fullContact)])
+    }
+
+    /// Export each member of a group as its own vCard string
+    func exportGroupVCards(_ group: CNGroup) throws -> [(contact: CNContact, vcard: String)] {
+        let predicate = CNContact.predicateForContactsInGroup(withIdentifier: group.identifier)
+        let contacts = try store.unifiedContacts(matching: predicate, keysToFetch: Self.vCardKeys + Self.basicKeys)
+        return try vCardStrings(for: contacts)
+    }
+
+    /// Export every contact as its own vCard string
+    func exportAllVCards() throws -> [(contact: CNContact, vcard: String)] {
+        let contacts = try fetchAll(keysToFetch: Self.vCardKeys + Self.basicKeys)
+        return try vCardStrings(for: contacts)
+    }
+
+    /// Serialize every contact not in `skipping` one at a time while the store enumerates
+    /// them, handing each card to `body` as soon as it is ready. Only one contact is held
+    /// at a time, and callers can save their progress as they go. An error thrown by
+    /// `body` stops the enumeration and is rethrown.
+    func forEachVCard(skipping ids: Set<String> = [], _ body: (CNContact, String) throws -> Void) throws {
+        // With progress reporting, a first pass over identifiers only gives the total
+        var total = 0
+        if progress != nil {
+            total = try contactCount(skipping: ids)
+            if total == 0 {
+                progress?.report(done: 0, total: 0)
+            }
+        }
+
+        let request = CNContactFetchRequest(keysToFetch: Self.vCardKeys + Self.basicKeys)
+        request.sortOrder = .userDefault
+        var done = 0
+        var failure: Error?
+        try store.enumerateContacts(with: request) { contact, stop in
+            if ids.contains(contact.identifier) {
+                return
+            }
+            do {
+                let card = try exported(contact)
+                try body(card.contact, card.vcard)
+            } catch {
+                failure = error
+                stop.pointee = true
+                return
+            }
+            done += 1
+            // Contacts added since the first pass would push done past it
+            progress?.report(done: done, total: max(total, done))
+        }
+        if let failure {
+            throw failure
+        }
+    }
+
+    /// Enumerate the contacts `request` fetches, reporting to `progress` as they arrive.
+    /// `limit` caps the reported total for callers that stop early.
+    private func enumerate(
+        _ request: CNContactFetchRequest,
+        limit: Int? = nil,
+        _ body: (CNContact, UnsafeMutablePointer<ObjCBool>) -> Void
+    ) throws {
+        guard let progress else {
+            try store.enumerateContacts(with: request, usingBlock: body)
+            return
+        }
+
+        let count = try contactCount()
+        let total = limit.map { min($0, count) } ?? count
+        if total == 0 {
+            progress.report(done: 0, total: 0)
+        }
+        var done = 0
+        try store.enumerateContacts(with: request) { contact, stop in
+            body(contact, stop)
+            done += 1
+            // Contacts added since the count would push done past it
+            progress.report(done: done, total: max(total, done))
+        }
+    }
+
+    /// Number of contacts not in `skipping`, from a pass over identifiers only
+    private func contactCount(skipping ids: Set<String> = []) throws -> Int {
+        var count = 0
+        let request = CNContactFetchRequest(keysToFetch: [CNContactIdentifierKey as CNKeyDescriptor])
+        try store.enumerateContacts(with: request) { contact, _ in
+            if !ids.contains(contact.identifier) {
+                count += 1
+            }
+        }
+        return count
+    }
+
+    /// Serialize each contact, reporting to `progress` as it goes
+    private func vCardStrings(for contacts: [CNContact]) throws -> [(contact: CNContact, vcard: String)] {
+        if contacts.isEmpty {
+            progress?.report(done: 0, total: 0)
+        }
+        return try contacts.enumerated().map { index, contact in
+            let card = try exported(contact)
+            progress?.report(done: index + 1, total: contacts.count)
+            return card
+        }
+    }
+
+    /// A contact fetched with `vCardKeys`, normalized and in label order, with its vCard.
+    /// JSON, CSV and `--only` cards are built from the returned contact, so they match
+    /// the vCard.
+    func exported(_ contact: CNContact) throws -> (contact: CNContact, vcard: String) {
+        let prepared = applyingLabelOrder(contact)
+        let data = try CNContactVCardSerialization.data(with: [prepared])
+        guard let vcard = String(data: data, encoding: .utf8) else {
+            throw ContactsError.exportFailed
+        }
+        return (prepared, vcard)
+    }
+
+    /// Get a contact's full-size image, if it has one
+    func getContactPhoto(id: String) throws -> Data? {
+        let predicate = CNContact.predicateForContacts(withIdentifiers: [id])
+        let keys = [CNContactImageDataKey as CNKeyDescriptor]
+        return try store.unifiedContacts(matching: predicate, keysToFetch: keys).first?.imageData
+    }
+
+    /// Export contact as vCard string
+    func exportVCardString(contact: CNContact) throws -> String {
+        let data = try exportVCard(contact: contact)
+        guard let string = String(data: data, encoding: .utf8) else {
+            throw ContactsError.exportFailed
+        }
+        return string
+    }
+}
+
+// MARK: - Errors
+
+enum ContactsError: Error, CustomStringConvertible {
+    /// Whether a fetch failed because the note key isn't allowed (no notes entitlement),
+    /// as opposed to the contact being gone or any other error
+    static func isNoteAccessError(_ error: Error) -> Bool {
+        guard let error = error as? CNError, error.code == .unauthorizedKeys else {
+            return false
+        }
+        // The error names the refused keys when it can
+        guard let keyPaths = error.userInfo[CNErrorUserInfoKeyPathsKey] as? [String] else {
+            return true
+        }
+        return keyPaths.contains(CNContactNoteKey)
+    }
+
+    case accessDenied
+    case contactNotFound
+    case groupNotFound
+    case unknownGroup(String)
+    case unknownSource(String, available: String)
+    case exportFailed
+    case notesUnavailable
+    case unknownField(String, available: String)
+    case invalidConfig(String, reason: String)
+    case queryNotFound(String)
+    case queryCycle([String])
+    case ambiguousID(String, matches: Int)
+    case ambiguousName(String, matches: Int)
+    case invalidVCard(String)
+    case invalidExport(String)
+    case appendFormatMismatch(String, format: String)
+    case jsonPathNoMatch(String)
+    case snapshotNotFound
+    case modificationDatesUnavailable
+    case noAddress
+    case addressIndexOutOfRange(Int, count: Int)
+    case mapsFailed(status: Int32)
+    case invalidReport(String)
+    case reportCommandMismatch(String, command: String)
+
+    var description: String {
+        switch self {
+        case .accessDenied:
+            return "Access to Contacts denied. Please grant access in System Settings > Privacy & Security > Contacts."
+        case .contactNotFound:
+            return "Contact not found"
+        case .groupNotFound:
+            return "Group not found"
+        case .unknownGroup(let name):
+            return "No group named '\(name)'. Use 'groups' to see the available groups."
+        case .unknownSource(let name, let available):
+            return "No source named '\(name)'. Available sources: \(available)"
+        case .exportFailed:
+            return "Failed to export contact"
+        case .notesUnavailable:
+            return "Contact notes are not accessible. Reading notes requires the Contacts notes entitlement."
+        case .unknownField(let name, let available):
+            return "Unknown field '\(name)'. Available fields: \(available)"
+        case .invalidConfig(let path, let reason):
+            return "Invalid config file \(path): \(reason)"
+        case .ambiguousID(let prefix, let matches):
+            return "ID prefix '\(prefix)' matches \(matches) contacts. Use a longer prefix or the full ID."
+        case .ambiguousName(let name, let matches):
+            return "'\(name)' matches \(matches) contacts. Use --id to pick one (see 'resolve \(name)')."
+        case .invalidVCard(let path):
+            return "Could not read any contacts from \(path). Is it a vCard (.vcf) file?"
+        case .invalidExport(let path):
+            return "\(path) is not a JSON export. Create one with 'export --all --output file.json'."
+        case .appendFormatMismatch(let path, let format):
+            return "\(path) doesn't look like a \(format) export. Not appending to it; use a new file or the matching --format."
+        case .jsonPathNoMatch(let path):
+            return "--jsonpath '\(path)' matched nothing"
+        case .snapshotNotFound:
+            return "No snapshot found. Save one first with 'list --snapshot'."
+        case .modificationDatesUnavailable:
+            return "Could not read contact creation and modification dates from the address book."
+        case .noAddress:
+            return "Contact has no address to open"
+        case .addressIndexOutOfRange(let index, let count):
+            return "--address-index \(index) is out of range; the contact has \(count) address(es)"
+        case .mapsFailed(let status):
+            return "Could not open Maps (open exited with status \(status))"
+        case .invalidReport(let path):
+            return "\(path) is not a report written with --report"
+        case .reportCommandMismatch(let path, let command):
+            return "\(path) is a report from '\(command)'. Retry it with that command."
+        case .queryNotFound(let name):
+            return "No saved query named '\(name)'. Use 'search --list-queries' to see saved queries."
+        case .queryCycle(let names):
+            return "Saved query '\(names[0])' runs itself (\(names.joined(separator: " -> "))). Fix it in \(Config.fileURL.path)."
+        }
+    }
+}
+
+// MARK: - CNContainer Extensions
+
+extension CNContainer {
+    /// Name as shown in Contacts.app; the local container often has no name of its own
+    var displayName: String {
+        if !name.isEmpty {
+            return name
+        }
+        return type == .local ? "On My Mac" : identifier
+    }
+}
+
+// MARK: - CNContact Extensions
+
+extension CNContact {
+    /// Single-valued text fields, with their fetch key and accessors
+    static var textFields: [(
+        name: String,
+        key: String,
+        read: KeyPath<CNContact, String>,
+        write: ReferenceWritableKeyPath<CNMutableContact, String>
+    )] {
+        [
+            ("firstName", CNContactGivenNameKey, \.givenName, \.givenName),
+            ("middleName", CNContactMiddleNameKey, \.middleName, \.middleName),
+            ("lastName", CNContactFamilyNameKey, \.familyName, \.familyName),
+            ("nickname", CNContactNicknameKey, \.nickname, \.nickname),
+            ("organization", CNContactOrganizationNameKey, \.organizationName, \.organizationName),
+            ("department", CNContactDepartmentNameKey, \.departmentName, \.departmentName),
+            ("jobTitle", CNContactJobTitleKey, \.jobTitle, \.jobTitle),
+        ]
+    }
+
+    /// Full name using formatter, in the name order set in Contacts
+    var fullName: String {
+        fullName(locale: nil)
+    }
+
+    /// Full name composed by `locale`'s conventions (--locale), e.g. family name first
+    /// in ja-JP. Nil follows the name order set in Contacts.
+    func fullName(locale: Locale?) -> String {
+        if let locale {
+            let name = nameComponents.formatted(PersonNameComponents.FormatStyle(style: .long, locale: locale))
+            if !name.isEmpty {
+                return name
+            }
+        }
+        return CNContactFormatter.string(from: self, style: .fullName)
+            ?? "\(givenName) \(familyName)".trimmingCharacters(in: .whitespaces)
+    }
+
+    /// The fetched name parts, for formatting with a given locale
+    private var nameComponents: PersonNameComponents {
+        func part(_ key: String, _ value: KeyPath<CNContact, String>) -> String? {
+            isKeyAvailable(key) && !self[keyPath: value].isEmpty ? self[keyPath: value] : nil
+        }
+        var components = PersonNameComponents()
+        components.namePrefix = part(CNContactNamePrefixKey, \.namePrefix)
+        components.givenName = part(CNContactGivenNameKey, \.givenName)
+        components.middleName = part(CNContactMiddleNameKey, \.middleName)
+        components.familyName = part(CNContactFamilyNameKey, \.familyName)
+        components.nameSuffix = part(CNContactNameSuffixKey, \.nameSuffix)
+        return components
+    }
+
+    /// Name to display: the organization for company cards, or when there is no person name
+    var displayName: String {
+        displayName(locale: nil)
+    }
+
+    /// `displayName` with the person name composed by `locale`, as in `fullName(locale:)`
+    func displayName(locale: Locale?) -> String {
+        let isCompany = isKeyAvailable(CNContactTypeKey) && contactType == .organization
+        let name = fullName(locale: locale)
+        if isCompany || name.isEmpty, isKeyAvailable(CNContactOrganizationNameKey), !organizationName.isEmpty {
+            return organizationName
+        }
+        return name
+    }
+
+    /// Keys `summaryLine` needs in addition to `basicKeys`
+    static var summaryKeys: [CNKeyDescriptor] {
+        [
+            CNContactPhoneNumbersKey as CNKeyDescriptor,
+            CNContactEmailAddressesKey as CNKeyDescriptor,
+        ]
+    }
+
+    /// One line with the name, organization, primary phone and primary email, e.g.
+    /// "Erik Fisher — Acme — +47 900 00 000 — erik@acme.com". Empty parts are left out.
+    /// Requires `basicKeys` plus `summaryKeys`
+    func summaryLine(redaction: Redaction = .none, nameLocale: Locale? = nil) -> String {
+        let phone = LabelOrder.sortLabeledByPreference(phoneNumbers, order: LabelOrder.defaultOrder).first
+        let email = LabelOrder.sortLabeledByPreference(emailAddresses, order: LabelOrder.defaultOrder).first
+        let name = displayName(locale: nameLocale)
+
+        var parts = [name]
+        if organizationName != name {
+            parts.append(organizationName)
+        }
+        parts.append(phone.map { redaction.phone($0.value.stringValue) } ?? "")
+        parts.append(email.map { redaction.email($0.value as String) } ?? "")
+        return parts.filter { !$0.isEmpty }.joined(separator: " — ")
+    }
+
+    /// Phonetic reading of the name in display order, or nil if no phonetic fields are set
+    /// Requires the phonetic name keys (included in `fullKeys`)
+    var phoneticName: String? {
+        let given = [phoneticGivenName, phoneticMiddleName].filter { !$0.isEmpty }
+        let family = phoneticFamilyName.isEmpty ? [] : [phoneticFamilyName]
+        if given.isEmpty && family.isEmpty {
+            return nil
+        }
+        let ordered = CNContactFormatter.nameOrder(for: self) == .familyNameFirst ? family + given : given + family
+        return ordered.joined(separator: " ")
+    }
+
+    /// Birthday as string (YYYY-MM-DD or --MM-DD if no year)
+    var birthdayString: String? {
+        guard let birthday else { return nil }
+        var components: [String] = []
+        if let year = birthday.year {
+            components.append(String(format: "%04d", year))
+        } else {
+            components.append("----")
+        }
+        if let month = birthday.month {
+            components.append(String(format: "%02d", month))
+        }
+        if let day = birthday.day {
+            components.append(String(format: "%02d", day))
+        }
+        return components.joined(separator: "-")
+    }
+
+    /// Age in whole years, if the birthday includes a year
+    var age: Int? {
+        guard let birthday, birthday.year != nil,
+              let date = Calendar(identifier: .gregorian).date(from: birthday)
+        else {
+            return nil
+        }
+        return Calendar.current.dateComponents([.year], from: date, to: Date()).year
+    }
+
+    /// Keys `isEffectivelyEmpty` needs: `fullKeys` plus the note and other dates
+    static var emptinessKeys: [CNKeyDescriptor] {
+        ContactsService.fullKeys + [
+            CNContactNoteKey as CNKeyDescriptor,
+            CNContactDatesKey as CNKeyDescriptor,
+        ]
+    }
+
+    /// True when the contact holds nothing at all: no name (including phonetic names and
+    /// nickname), organization, department, job title or note, no phone, email, address,
+    /// URL, social profile, instant message address or related name, no birthday or other
+    /// date, and no photo. Requires `emptinessKeys`.
+    var isEffectivelyEmpty: Bool {
+        let text = [
+            givenName, middleName, familyName, phoneticGivenName, phoneticMiddleName, phoneticFamilyName,
+            nickname, organizationName, departmentName, jobTitle, note,
+        ]
+        return text.allSatisfy { $0.trimmingCharacters(in: .whitespacesAndNewlines).isEmpty }
+            && phoneNumbers.isEmpty
+            && emailAddresses.isEmpty
+            && postalAddresses.isEmpty
+            && urlAddresses.isEmpty
+            && socialProfiles.isEmpty
+            && instantMessageAddresses.isEmpty
+            && contactRelations.isEmpty
+            && birthday == nil
+            && dates.isEmpty
+            && !imageDataAvailable
+    }
+
+    /// Keys `completenessScore` needs in addition to `basicKeys`
+    static var completenessKeys: [CNKeyDescriptor] {
+        [
+            CNContactPhoneNumbersKey as CNKeyDescriptor,
+            CNContactEmailAddressesKey as CNKeyDescriptor,
+            CNContactBirthdayKey as CNKeyDescriptor,
+        ]
+    }
+
+    /// How filled-in the contact is: one point each for a name, organization, birthday
+    /// and note, plus one per phone number and email address.
+    /// The note is passed in because reading it needs the notes entitlement.
+    func completenessScore(hasNote: Bool) -> Int {
+        func filled(_ value: String) -> Bool {
+            !value.trimmingCharacters(in: .whitespacesAndNewlines).isEmpty
+        }
+
+        var score = 0
+        if filled(givenName) || filled(familyName) {
+            score += 1
+        }
+        if filled(organizationName) {
+            score += 1
+        }
+        if birthday != nil {
+            score += 1
+        }
+        if hasNote {
+            score += 1
+        }
+        return score + phoneNumbers.count + emailAddresses.count
+    }
+
+    /// First phone number
+    var firstPhone: String? {
+        phoneNumbers.first?.value.stringValue
+    }
+
+    /// First email
+    var firstEmail: String? {
+        emailAddresses.first?.value as String?
+    }
+}
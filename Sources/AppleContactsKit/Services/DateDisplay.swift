@@ -0,0 +1,70 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+/// How dates appear in human-readable output (`--locale`, `--date-format`).
+/// JSON output always uses ISO 8601 dates regardless of these settings.
+struct DateDisplay {
+    enum Style: String, ExpressibleByArgument, CaseIterable {
+        /// The locale's usual numeric order, e.g. 14.03.1985 for nb-NO or 03/14/1985 for en-US
+        case locale
+        /// 1985-03-14, or --03-14 without a year
+        case iso
+    }
+
+    let locale: Locale
+    let style: Style
+
+    init(locale: Locale = .current, style: Style = .locale) {
+        self.locale = locale
+        self.style = style
+    }
+
+    /// The contact's birthday, with or without a year. Requires `CNContactBirthdayKey`
+    func birthday(of contact: CNContact) -> String? {
+        guard style == .locale, let components = contact.birthday,
+              let month = components.month, let day = components.day
+        else {
+            return contact.birthdayString
+        }
+
+        // Any leap year works for year-less dates, so February 29 can be formatted
+        let hasYear = components.year != nil
+        let calendar = Self.utcCalendar
+        guard let date = calendar.date(from: DateComponents(year: components.year ?? 2000, month: month, day: day)) else {
+            return contact.birthdayString
+        }
+        let formatter = DateFormatter()
+        formatter.locale = locale
+        formatter.calendar = calendar
+        formatter.timeZone = calendar.timeZone
+        formatter.setLocalizedDateFormatFromTemplate(hasYear ? "yyyyMMdd" : "MMdd")
+        return formatter.string(from: date)
+    }
+
+    /// A point in time, such as a modification date, without the time of day
+    func date(_ date: Date) -> String {
+        let formatter = DateFormatter()
+        formatter.locale = locale
+        if style == .iso {
+            formatter.locale = Locale(identifier: "en_US_POSIX")
+            formatter.dateFormat = "yyyy-MM-dd"
+        } else {
+            formatter.dateStyle = .medium
+            formatter.timeStyle = .none
+        }
+        return formatter.string(from: date)
+    }
+
+    /// Whether `identifier` names a locale Foundation knows, e.g. "nb-NO", "nb_NO" or "de"
+    static func isKnownLocale(_ identifier: String) -> Bool {
+        let canonical = Locale.canonicalIdentifier(from: identifier)
+        return Locale.availableIdentifiers.contains(canonical)
+    }
+
+    private static var utcCalendar: Calendar {
+        var calendar = Calendar(identifier: .gregorian)
+        calendar.timeZone = TimeZone(identifier: "UTC")!
+        return calendar
+    }
+}
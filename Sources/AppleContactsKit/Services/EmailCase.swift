@@ -0,0 +1,46 @@
+import Contacts
+import Foundation
+
+/// Lowercasing of email addresses (`--lowercase-emails`, `export --normalize`).
+/// Domains are case-insensitive, so lowercasing them never changes where mail goes.
+/// The local part is case-sensitive in theory, though almost no provider treats it that way.
+enum EmailCase {
+    /// The address with its domain lowercased, or the whole address with `wholeAddress`.
+    /// Values without an @ are returned unchanged unless the whole address is lowercased.
+    static func normalizeEmail(_ address: String, wholeAddress: Bool = false) -> String {
+        if wholeAddress {
+            return address.lowercased()
+        }
+        guard let at = address.lastIndex(of: "@") else {
+            return address
+        }
+        return String(address[..<at]) + address[at...].lowercased()
+    }
+
+    /// The lowercased domain of an address, or nil if it has none
+    static func domain(of address: String) -> String? {
+        guard let at = address.lastIndex(of: "@") else {
+            return nil
+        }
+        let domain = address[address.index(after: at)...].trimmingCharacters(in: .whitespaces).lowercased()
+        return domain.isEmpty ? nil : domain
+    }
+
+    /// Copy of a contact with its email addresses normalized. Returns the contact itself
+    /// if emails weren't fetched or nothing changes.
+    static func normalizing(_ contact: CNContact, wholeAddress: Bool = false) -> CNContact {
+        guard contact.isKeyAvailable(CNContactEmailAddressesKey) else {
+            return contact
+        }
+        let emails = contact.emailAddresses.map { email in
+            email.settingValue(normalizeEmail(email.value as String, wholeAddress: wholeAddress) as NSString)
+        }
+        guard zip(emails, contact.emailAddresses).contains(where: { $0.value != $1.value }) else {
+            return contact
+        }
+
+        let mutable = contact.mutableCopy() as! CNMutableContact
+        mutable.emailAddresses = emails
+        return mutable.copy() as! CNContact
+    }
+}
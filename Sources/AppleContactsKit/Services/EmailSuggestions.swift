@@ -0,0 +1,82 @@
+import Foundation
+
+/// Detection of malformed email addresses and suggested corrections
+enum EmailSuggestions {
+    /// Common misspellings of popular email domains.
+    /// Kept deliberately small: only typos that are never real domains people use.
+    static let domainTypos: [String: String] = [
+        "gmial.com": "gmail.com",
+        "gmai.com": "gmail.com",
+        "gamil.com": "gmail.com",
+        "gnail.com": "gmail.com",
+        "gmaill.com": "gmail.com",
+        "gmail.con": "gmail.com",
+        "gmail.cmo": "gmail.com",
+        "hotmial.com": "hotmail.com",
+        "hotmal.com": "hotmail.com",
+        "hotmail.con": "hotmail.com",
+        "yahooo.com": "yahoo.com",
+        "yaho.com": "yahoo.com",
+        "yahoo.con": "yahoo.com",
+        "outlok.com": "outlook.com",
+        "outlook.con": "outlook.com",
+        "iclod.com": "icloud.com",
+        "icoud.com": "icloud.com",
+        "icloud.con": "icloud.com",
+    ]
+
+    /// Describe what is wrong with an address, or nil if it looks fine
+    static func problem(with address: String) -> String? {
+        let trimmed = address.trimmingCharacters(in: .whitespaces)
+        let parts = trimmed.split(separator: "@", omittingEmptySubsequences: false)
+
+        if trimmed != address {
+            return "leading or trailing whitespace"
+        }
+        if parts.count == 1 {
+            return "missing @"
+        }
+        if parts.count > 2 {
+            return "more than one @"
+        }
+        if parts[0].isEmpty || parts[1].isEmpty {
+            return "empty local part or domain"
+        }
+
+        let domain = parts[1].lowercased()
+        if domain.hasPrefix(".") || domain.hasSuffix(".") || domain.contains("..") {
+            return "misplaced dot in domain"
+        }
+        if !domain.contains(".") {
+            return "domain has no top-level domain"
+        }
+        if domainTypos[domain] != nil {
+            return "likely misspelled domain"
+        }
+        return nil
+    }
+
+    /// Suggest a corrected address, or nil if no confident fix is known
+    static func suggestFix(for address: String) -> String? {
+        var fixed = address.trimmingCharacters(in: .whitespaces)
+        let parts = fixed.split(separator: "@", omittingEmptySubsequences: false)
+        guard parts.count == 2, !parts[0].isEmpty, !parts[1].isEmpty else {
+            return nil
+        }
+
+        var domain = String(parts[1])
+        while domain.contains("..") {
+            domain = domain.replacingOccurrences(of: "..", with: ".")
+        }
+        domain = domain.trimmingCharacters(in: CharacterSet(charactersIn: "."))
+        if let corrected = domainTypos[domain.lowercased()] {
+            domain = corrected
+        }
+        guard domain.contains(".") else {
+            return nil
+        }
+
+        fixed = "\(parts[0])@\(domain)"
+        return fixed == address ? nil : fixed
+    }
+}
@@ -0,0 +1,101 @@
+import Foundation
+
+/// Differences between two JSON exports (`export --output file.json`), matched by contact ID
+struct ExportDiff {
+    /// One contact as it appears in an export file
+    typealias Record = [String: Any]
+
+    struct FieldChange {
+        let field: String
+        /// Empty when the field is missing on that side
+        let old: String
+        let new: String
+    }
+
+    struct Modified {
+        let id: String
+        let name: String
+        let changes: [FieldChange]
+    }
+
+    let added: [Record]
+    let removed: [Record]
+    let modified: [Modified]
+
+    var isEmpty: Bool {
+        added.isEmpty && removed.isEmpty && modified.isEmpty
+    }
+
+    /// Compare two exports. Added and modified contacts keep the new file's order,
+    /// removed ones the old file's.
+    init(old: [Record], new: [Record]) {
+        let oldByID = Dictionary(old.map { (Self.id(of: $0), $0) }, uniquingKeysWith: { first, _ in first })
+        let newIDs = Set(new.map(Self.id(of:)))
+
+        var added: [Record] = []
+        var modified: [Modified] = []
+        for record in new {
+            guard let previous = oldByID[Self.id(of: record)] else {
+                added.append(record)
+                continue
+            }
+
+            let fields = Set(previous.keys).union(record.keys).subtracting(["id"]).sorted()
+            let changes = fields.compactMap { field -> FieldChange? in
+                let before = Self.text(previous[field])
+                let after = Self.text(record[field])
+                return before == after ? nil : FieldChange(field: field, old: before, new: after)
+            }
+            if !changes.isEmpty {
+                modified.append(Modified(id: Self.id(of: record), name: Self.name(of: record), changes: changes))
+            }
+        }
+
+        self.added = added
+        self.removed = old.filter { !newIDs.contains(Self.id(of: $0)) }
+        self.modified = modified
+    }
+
+    /// Read the contacts from an export file
+    static func load(_ path: String) throws -> [Record] {
+        let data = try Data(contentsOf: URL(fileURLWithPath: path))
+        guard let records = try? JSONSerialization.jsonObject(with: data) as? [Record] else {
+            throw ContactsError.invalidExport(path)
+        }
+        return records
+    }
+
+    static func id(of record: Record) -> String {
+        record["id"] as? String ?? ""
+    }
+
+    static func name(of record: Record) -> String {
+        let name = record["name"] as? String ?? ""
+        return name.isEmpty ? "(no name)" : name
+    }
+
+    /// Display form of a field value; labeled lists read as "value (label), ..."
+    static func text(_ value: Any?) -> String {
+        switch value {
+        case nil:
+            return ""
+        case let string as String:
+            return string
+        case let entries as [[String: String]]:
+            return entries.map { entry in
+                let value = entry["value"] ?? ""
+                guard let label = entry["label"], !label.isEmpty else { return value }
+                return "\(value) (\(label))"
+            }.joined(separator: ", ")
+        default:
+            // Wrap in an array so scalars serialize too, then strip the brackets
+            if let value, JSONSerialization.isValidJSONObject([value]),
+               let data = try? JSONSerialization.data(withJSONObject: [value], options: .sortedKeys),
+               let string = String(data: data, encoding: .utf8)
+            {
+                return String(string.dropFirst().dropLast())
+            }
+            return value.map { "\($0)" } ?? ""
+        }
+    }
+}
@@ -0,0 +1,197 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+/// A contact exported as a vCard, together with the contact it came from
+typealias ExportedContact = (contact: CNContact, vcard: String)
+
+/// File formats supported by `export`
+enum ExportFormat: String, ExpressibleByArgument, CaseIterable {
+    case vcard
+    case json
+    case csv
+    case mecard
+
+    /// UTF-8 byte order mark, which Excel needs to read CSV files as UTF-8
+    static let byteOrderMark = "\u{FEFF}"
+
+    /// Infer the format from a file extension (.vcf, .vcard, .json, .csv)
+    init?(path: String) {
+        switch URL(fileURLWithPath: path).pathExtension.lowercased() {
+        case "vcf", "vcard": self = .vcard
+        case "json": self = .json
+        case "csv": self = .csv
+        default: return nil
+        }
+    }
+
+    /// Encode contacts in this format. `multivalue` sets the CSV layout and `idFormat` how
+    /// IDs are written in JSON and CSV; vCards keep the contact's own UID.
+    func encode(_ exported: [ExportedContact], multivalue: CSVMultivalue = .wide, idFormat: IDFormat = .full) -> String {
+        switch self {
+        case .vcard:
+            return exported.map { $0.vcard.hasSuffix("\n") ? $0.vcard : $0.vcard + "\n" }.joined()
+        case .json:
+            return Self.json(exported.map { $0.contact }, idFormat: idFormat)
+        case .csv:
+            return Self.csv(exported.map { $0.contact }, multivalue: multivalue, idFormat: idFormat)
+        case .mecard:
+            return exported.map { MeCard.encode($0.contact) + "\n" }.joined()
+        }
+    }
+
+    /// `existing` file content with contacts added in this format, for `export --append`:
+    /// vCard and MeCard files are concatenated, CSV rows are added without a second header,
+    /// and JSON arrays are merged. Returns nil if the content is in a different format,
+    /// including a CSV file with the other `multivalue` layout.
+    func appending(_ exported: [ExportedContact], to existing: String, multivalue: CSVMultivalue = .wide, idFormat: IDFormat = .full) -> String? {
+        appending(encoded: encode(exported, multivalue: multivalue, idFormat: idFormat), to: existing, multivalue: multivalue)
+    }
+
+    /// Like `appending(_:to:multivalue:idFormat:)`, for contacts already encoded in this
+    /// format, so callers can change the new text (e.g. --ascii-only) without touching `existing`
+    func appending(encoded: String, to existing: String, multivalue: CSVMultivalue = .wide) -> String? {
+        if existing.trimmingCharacters(in: .whitespacesAndNewlines).isEmpty {
+            return encoded
+        }
+        let separated = existing.hasSuffix("\n") ? existing : existing + "\n"
+
+        switch self {
+        case .vcard:
+            guard existing.trimmingCharacters(in: .whitespacesAndNewlines).uppercased().hasPrefix("BEGIN:VCARD") else {
+                return nil
+            }
+            return separated + encoded
+        case .mecard:
+            guard existing.hasPrefix("MECARD:") else { return nil }
+            return separated + encoded
+        case .csv:
+            let firstLine = existing.components(separatedBy: .newlines).first ?? ""
+            let header = Self.csvHeader(multivalue)
+            guard firstLine == header || firstLine == Self.byteOrderMark + header else {
+                return nil
+            }
+            let rows = encoded.components(separatedBy: "\n").dropFirst().joined(separator: "\n")
+            return separated + rows
+        case .json:
+            guard let previous = (try? JSONSerialization.jsonObject(with: Data(existing.utf8))) as? [Any],
+                  let added = (try? JSONSerialization.jsonObject(with: Data(encoded.utf8))) as? [Any],
+                  let jsonData = try? JSONSerialization.data(withJSONObject: previous + added, options: [.prettyPrinted, .sortedKeys]),
+                  let jsonString = String(data: jsonData, encoding: .utf8)
+            else {
+                return nil
+            }
+            return jsonString + "\n"
+        }
+    }
+
+    private static func labeled<T: NSCopying & NSSecureCoding>(
+        _ values: [CNLabeledValue<T>],
+        _ text: (T) -> String
+    ) -> [[String: String]] {
+        values.map {
+            [
+                "label": LabelOrder.displayLabel($0.label),
+                "value": text($0.value),
+            ]
+        }
+    }
+
+    private static func json(_ contacts: [CNContact], idFormat: IDFormat) -> String {
+        let data = contacts.map { contact -> [String: Any] in
+            var entry: [String: Any] = [
+                "id": idFormat.apply(contact.identifier),
+                "name": contact.displayName,
+                "firstName": contact.givenName,
+                "lastName": contact.familyName,
+                "organization": contact.organizationName,
+            ]
+            if contact.isKeyAvailable(CNContactPhoneNumbersKey) {
+                entry["phones"] = labeled(contact.phoneNumbers) { $0.stringValue }
+            }
+            if contact.isKeyAvailable(CNContactEmailAddressesKey) {
+                entry["emails"] = labeled(contact.emailAddresses) { $0 as String }
+            }
+            return entry
+        }
+
+        guard let jsonData = try? JSONSerialization.data(withJSONObject: data, options: [.prettyPrinted, .sortedKeys]),
+              let jsonString = String(data: jsonData, encoding: .utf8)
+        else {
+            return "[]"
+        }
+        return jsonString + "\n"
+    }
+
+    private static func csvHeader(_ multivalue: CSVMultivalue) -> String {
+        switch multivalue {
+        case .wide:
+            return CSV.row(["Name", "First Name", "Last Name", "Organization", "Phones", "Emails", "ID"])
+        case .expand:
+            return CSV.row(["Name", "First Name", "Last Name", "Organization", "Field", "Label Type", "Value", "ID"])
+        }
+    }
+
+    private static func csv(_ contacts: [CNContact], multivalue: CSVMultivalue, idFormat: IDFormat) -> String {
+        var lines = [csvHeader(multivalue)]
+        for contact in contacts {
+            if multivalue == .expand {
+                lines += expandedRows(contact, id: idFormat.apply(contact.identifier))
+                continue
+            }
+
+            let phones = contact.isKeyAvailable(CNContactPhoneNumbersKey)
+                ? contact.phoneNumbers.map { $0.value.stringValue } : []
+            let emails = contact.isKeyAvailable(CNContactEmailAddressesKey)
+                ? contact.emailAddresses.map { $0.value as String } : []
+            lines.append(CSV.row([
+                contact.displayName,
+                contact.givenName,
+                contact.familyName,
+                contact.organizationName,
+                phones.joined(separator: "; "),
+                emails.joined(separator: "; "),
+                idFormat.apply(contact.identifier),
+            ]))
+        }
+        return lines.joined(separator: "\n") + "\n"
+    }
+
+    /// One row per phone number and email address, each repeating the contact's name,
+    /// organization and ID. A contact with neither still gets one row, with the value
+    /// columns empty, so no contact is dropped.
+    private static func expandedRows(_ contact: CNContact, id: String) -> [String] {
+        var values: [(field: String, label: String, value: String)] = []
+        if contact.isKeyAvailable(CNContactPhoneNumbersKey) {
+            values += contact.phoneNumbers.map { ("phone", LabelOrder.displayLabel($0.label), $0.value.stringValue) }
+        }
+        if contact.isKeyAvailable(CNContactEmailAddressesKey) {
+            values += contact.emailAddresses.map { ("email", LabelOrder.displayLabel($0.label), $0.value as String) }
+        }
+        if values.isEmpty {
+            values = [("", "", "")]
+        }
+
+        return values.map { entry in
+            CSV.row([
+                contact.displayName,
+                contact.givenName,
+                contact.familyName,
+                contact.organizationName,
+                entry.field,
+                entry.label,
+                entry.value,
+                id,
+            ])
+        }
+    }
+}
+
+/// How CSV export lays out contacts with several phone numbers or email addresses
+/// (`export --multivalue`)
+enum CSVMultivalue: String, ExpressibleByArgument, CaseIterable {
+    /// One row per contact, with all phones (and all emails) joined by "; " in one column
+    case wide
+    /// One row per phone number and email address, repeating the contact's other columns
+    case expand
+}
@@ -0,0 +1,60 @@
+import Foundation
+
+/// Record of the contacts a per-contact export (`export --all --split-by contact`) has written,
+/// so an interrupted export can pick up where it stopped with `--resume`.
+/// Stored next to the cards as one `id<TAB>filename` line per contact, appended as each
+/// card is written, so it survives the process being killed.
+final class ExportJournal {
+    static let filename = ".apple-contacts-export.journal"
+
+    let url: URL
+    /// Filenames already written, keyed by contact identifier
+    private(set) var entries: [String: String] = [:]
+    private var handle: FileHandle?
+
+    init(directory: URL) {
+        url = directory.appendingPathComponent(Self.filename)
+    }
+
+    var exists: Bool {
+        FileManager.default.fileExists(atPath: url.path)
+    }
+
+    /// Read the entries of an earlier run. Lines cut off by an interruption are ignored.
+    func load() throws {
+        let text = try String(contentsOf: url, encoding: .utf8)
+        for line in text.split(separator: "\n") {
+            let parts = line.split(separator: "\t", maxSplits: 1)
+            guard parts.count == 2 else { continue }
+            entries[String(parts[0])] = String(parts[1])
+        }
+    }
+
+    /// Start a new, empty journal, replacing any earlier one
+    func reset() throws {
+        entries = [:]
+        try Data().write(to: url)
+    }
+
+    /// Note that a contact's card has been written
+    func record(id: String, filename: String) throws {
+        if handle == nil {
+            if !exists {
+                try Data().write(to: url)
+            }
+            handle = try FileHandle(forWritingTo: url)
+            try handle?.seekToEnd()
+        }
+        try handle?.write(contentsOf: Data("\(id)\t\(filename)\n".utf8))
+        entries[id] = filename
+    }
+
+    /// Remove the journal once the export is complete
+    func remove() throws {
+        try handle?.close()
+        handle = nil
+        if exists {
+            try FileManager.default.removeItem(at: url)
+        }
+    }
+}
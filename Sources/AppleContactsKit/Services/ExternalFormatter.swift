@@ -0,0 +1,66 @@
+import Foundation
+
+/// Formats output with a user-supplied shell command (`--format-command`): each contact
+/// is written to the command's stdin as one line of JSON, and whatever it prints is shown
+struct ExternalFormatter {
+    let command: String
+    let log: Logger
+
+    /// Run the command once per entry. A failing run is reported on stderr and the
+    /// remaining entries are still processed. Returns the number of failed runs.
+    @discardableResult
+    func run(_ entries: [[String: Any]]) -> Int {
+        var failures = 0
+        for entry in entries {
+            do {
+                let output = try format(entry)
+                print(output, terminator: output.hasSuffix("\n") || output.isEmpty ? "" : "\n")
+            } catch {
+                failures += 1
+                log.error("format command failed: \(error)", ["id": entry["id"] ?? ""])
+            }
+        }
+        return failures
+    }
+
+    private struct CommandFailed: Error, CustomStringConvertible {
+        let status: Int32
+        var description: String { "exit status \(status)" }
+    }
+
+    private func format(_ entry: [String: Any]) throws -> String {
+        let input = try JSONSerialization.data(withJSONObject: entry, options: .sortedKeys) + Data("\n".utf8)
+
+        let process = Process()
+        process.executableURL = URL(fileURLWithPath: "/bin/sh")
+        process.arguments = ["-c", command]
+        let stdin = Pipe()
+        let stdout = Pipe()
+        process.standardInput = stdin
+        process.standardOutput = stdout
+        try process.run()
+
+        // A command that exits without reading stdin must not kill us with SIGPIPE. Set on
+        // this pipe only, so writes to our own stdout still stop on a closed pipe.
+        let writer = stdin.fileHandleForWriting
+        _ = fcntl(writer.fileDescriptor, F_SETNOSIGPIPE, 1)
+
+        // Write from another thread while this one reads: a command that prints before it
+        // has read everything (or a contact larger than the pipe buffer) would otherwise
+        // block on a full stdout pipe while we block writing its stdin.
+        // Write errors only mean the command didn't read its input.
+        let written = DispatchGroup()
+        DispatchQueue.global().async(group: written) {
+            try? writer.write(contentsOf: input)
+            try? writer.close()
+        }
+        let output = stdout.fileHandleForReading.readDataToEndOfFile()
+        written.wait()
+        process.waitUntilExit()
+
+        guard process.terminationStatus == 0 else {
+            throw CommandFailed(status: process.terminationStatus)
+        }
+        return String(decoding: output, as: UTF8.self)
+    }
+}
@@ -0,0 +1,59 @@
+import Contacts
+import Foundation
+
+/// How many contacts have each field filled in (`report field-coverage`)
+struct FieldCoverage {
+    struct Entry {
+        let field: String
+        let count: Int
+        /// Share of all contacts, 0-100
+        let percent: Double
+    }
+
+    let total: Int
+    let entries: [Entry]
+
+    /// Fields that are checked, in report order. Contacts must be fetched with `ContactsService.fullKeys`.
+    static var fields: [(name: String, isSet: (CNContact) -> Bool)] {
+        [
+            ("firstName", { filled($0.givenName) }),
+            ("middleName", { filled($0.middleName) }),
+            ("lastName", { filled($0.familyName) }),
+            ("nickname", { filled($0.nickname) }),
+            ("organization", { filled($0.organizationName) }),
+            ("department", { filled($0.departmentName) }),
+            ("jobTitle", { filled($0.jobTitle) }),
+            ("birthday", { $0.birthday != nil }),
+            ("phones", { !$0.phoneNumbers.isEmpty }),
+            ("emails", { !$0.emailAddresses.isEmpty }),
+            ("addresses", { !$0.postalAddresses.isEmpty }),
+            ("urls", { !$0.urlAddresses.isEmpty }),
+            ("socialProfiles", { !$0.socialProfiles.isEmpty }),
+            ("instantMessaging", { !$0.instantMessageAddresses.isEmpty }),
+            ("relations", { !$0.contactRelations.isEmpty }),
+            ("photo", { $0.imageDataAvailable }),
+        ]
+    }
+
+    /// Coverage of every field. `notedIDs` holds the contacts with a note, or nil when notes
+    /// can't be read, in which case the note row is left out.
+    init(contacts: [CNContact], notedIDs: Set<String>?) {
+        let total = contacts.count
+        self.total = total
+        func entry(_ field: String, _ count: Int) -> Entry {
+            Entry(field: field, count: count, percent: total == 0 ? 0 : Double(count) * 100 / Double(total))
+        }
+
+        var entries = Self.fields.map { field in
+            entry(field.name, contacts.filter(field.isSet).count)
+        }
+        if let notedIDs {
+            entries.append(entry("note", contacts.filter { notedIDs.contains($0.identifier) }.count))
+        }
+        self.entries = entries
+    }
+
+    private static func filled(_ value: String) -> Bool {
+        !value.trimmingCharacters(in: .whitespacesAndNewlines).isEmpty
+    }
+}
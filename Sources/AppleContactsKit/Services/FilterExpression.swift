@@ -0,0 +1,478 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+/// A small boolean expression language for `list --filter`, evaluated per contact after
+/// fetching, e.g. `org == "Acme" && len(phones) > 0`.
+///
+/// Operators, loosest first: `||`, `&&`, `!`, then `==` `!=` `<` `<=` `>` `>=` `in`.
+/// Values are strings, numbers, true/false, nil and lists (phones, emails, ...).
+/// `&&`, `||`, `!` and the final result use truthiness: nil, false, 0, "" and empty
+/// lists are false. Comparisons with nil (e.g. `age > 30` without a birth year) are false.
+struct FilterExpression {
+    enum Value: Equatable {
+        case string(String)
+        case number(Double)
+        case bool(Bool)
+        case list([String])
+        case null
+
+        var isTruthy: Bool {
+            switch self {
+            case .string(let string): return !string.isEmpty
+            case .number(let number): return number != 0
+            case .bool(let bool): return bool
+            case .list(let list): return !list.isEmpty
+            case .null: return false
+            }
+        }
+
+        var typeName: String {
+            switch self {
+            case .string: return "string"
+            case .number: return "number"
+            case .bool: return "boolean"
+            case .list: return "list"
+            case .null: return "nil"
+            }
+        }
+    }
+
+    indirect enum Node {
+        case literal(Value)
+        case field(String)
+        case not(Node)
+        case binary(String, Node, Node)
+        case call(String, [Node])
+    }
+
+    /// Values a filter can read from a contact, by name
+    static var fields: [String: (CNContact, [String]) -> Value] {
+        [
+            "id": { c, _ in .string(c.identifier) },
+            "name": { c, _ in .string(c.fullName) },
+            "firstName": { c, _ in .string(c.givenName) },
+            "middleName": { c, _ in .string(c.middleName) },
+            "lastName": { c, _ in .string(c.familyName) },
+            "nickname": { c, _ in .string(c.nickname) },
+            "org": { c, _ in .string(c.organizationName) },
+            "department": { c, _ in .string(c.departmentName) },
+            "jobTitle": { c, _ in .string(c.jobTitle) },
+            "email": { c, _ in c.firstEmail.map(Value.string) ?? .null },
+            "phone": { c, _ in c.firstPhone.map(Value.string) ?? .null },
+            "birthday": { c, _ in c.birthdayString.map(Value.string) ?? .null },
+            "age": { c, _ in c.age.map { .number(Double($0)) } ?? .null },
+            "phones": { c, _ in .list(c.phoneNumbers.map { $0.value.stringValue }) },
+            "emails": { c, _ in .list(c.emailAddresses.map { $0.value as String }) },
+            "addresses": { c, _ in
+                .list(c.postalAddresses.map { CNPostalAddressFormatter.string(from: $0.value, style: .mailingAddress) })
+            },
+            "urls": { c, _ in .list(c.urlAddresses.map { $0.value as String }) },
+            "phoneLabels": { c, _ in .list(c.phoneNumbers.map { $0.label ?? "" }) },
+            "emailLabels": { c, _ in .list(c.emailAddresses.map { $0.label ?? "" }) },
+            "groups": { _, groups in .list(groups) },
+        ]
+    }
+
+    /// Helper functions and how many arguments each takes
+    static let functions: [String: Int] = [
+        "len": 1,
+        "lower": 1,
+        "cleanLabel": 1,
+        "contains": 2,
+        "startsWith": 2,
+        "endsWith": 2,
+        "matches": 2,
+    ]
+
+    /// Keys a contact must be fetched with for every field to be readable
+    static var requiredKeys: [CNKeyDescriptor] {
+        ContactsService.fullKeys
+    }
+
+    let expression: String
+    let root: Node
+    private let fieldValues = Self.fields
+    /// matches() patterns given as literals, compiled once while parsing
+    private let regexes: [String: NSRegularExpression]
+
+    init(parsing expression: String) throws {
+        self.expression = expression
+        var parser = Parser(expression: expression)
+        root = try parser.parse()
+
+        var regexes: [String: NSRegularExpression] = [:]
+        func compile(_ node: Node) throws {
+            switch node {
+            case .literal, .field:
+                break
+            case .not(let operand):
+                try compile(operand)
+            case .binary(_, let left, let right):
+                try compile(left)
+                try compile(right)
+            case .call(let name, let arguments):
+                if name == "matches", case .literal(.string(let pattern)) = arguments[1], regexes[pattern] == nil {
+                    guard let regex = try? NSRegularExpression(pattern: pattern) else {
+                        throw ValidationError("Invalid --filter '\(expression)': invalid regular expression '\(pattern)' in matches()")
+                    }
+                    regexes[pattern] = regex
+                }
+                try arguments.forEach(compile)
+            }
+        }
+        try compile(root)
+        self.regexes = regexes
+    }
+
+    /// Whether the expression reads `groups`, which needs an extra fetch
+    var usesGroups: Bool {
+        func uses(_ node: Node) -> Bool {
+            switch node {
+            case .literal: return false
+            case .field(let name): return name == "groups"
+            case .not(let operand): return uses(operand)
+            case .binary(_, let left, let right): return uses(left) || uses(right)
+            case .call(_, let arguments): return arguments.contains(where: uses)
+            }
+        }
+        return uses(root)
+    }
+
+    /// Whether the contact passes the filter. `groups` are the names of its groups.
+    func matches(_ contact: CNContact, groups: [String] = []) throws -> Bool {
+        try evaluate(root, contact, groups).isTruthy
+    }
+
+    private func evaluate(_ node: Node, _ contact: CNContact, _ groups: [String]) throws -> Value {
+        switch node {
+        case .literal(let value):
+            return value
+        case .field(let name):
+            return fieldValues[name]?(contact, groups) ?? .null
+        case .not(let operand):
+            return .bool(!(try evaluate(operand, contact, groups).isTruthy))
+        case .binary("&&", let left, let right):
+            return .bool(try evaluate(left, contact, groups).isTruthy && evaluate(right, contact, groups).isTruthy)
+        case .binary("||", let left, let right):
+            return .bool(try evaluate(left, contact, groups).isTruthy || evaluate(right, contact, groups).isTruthy)
+        case .binary(let op, let left, let right):
+            return try compare(op, evaluate(left, contact, groups), evaluate(right, contact, groups))
+        case .call(let name, let arguments):
+            return try call(name, arguments.map { try evaluate($0, contact, groups) })
+        }
+    }
+
+    private func compare(_ op: String, _ left: Value, _ right: Value) throws -> Value {
+        switch op {
+        case "==":
+            return .bool(left == right)
+        case "!=":
+            return .bool(left != right)
+        case "in":
+            switch (left, right) {
+            case (.string(let needle), .list(let list)):
+                return .bool(list.contains(needle))
+            case (.string(let needle), .string(let haystack)):
+                return .bool(haystack.contains(needle))
+            case (_, .null), (.null, _):
+                return .bool(false)
+            default:
+                throw failure("'in' needs a string on the left and a string or list on the right, not \(left.typeName) and \(right.typeName)")
+            }
+        default:
+            let order: ComparisonResult
+            switch (left, right) {
+            case (.null, _), (_, .null):
+                return .bool(false)
+            case (.number(let a), .number(let b)):
+                order = a < b ? .orderedAscending : a > b ? .orderedDescending : .orderedSame
+            case (.string(let a), .string(let b)):
+                order = a.compare(b)
+            default:
+                throw failure("cannot compare \(left.typeName) with \(right.typeName) using \(op)")
+            }
+            switch op {
+            case "<": return .bool(order == .orderedAscending)
+            case "<=": return .bool(order != .orderedDescending)
+            case ">": return .bool(order == .orderedDescending)
+            default: return .bool(order != .orderedAscending)
+            }
+        }
+    }
+
+    private func call(_ name: String, _ arguments: [Value]) throws -> Value {
+        // String helpers apply to each element of a list
+        func mapStrings(_ value: Value, _ transform: (String) -> String) throws -> Value {
+            switch value {
+            case .string(let string): return .string(transform(string))
+            case .list(let list): return .list(list.map(transform))
+            case .null: return .null
+            default: throw failure("\(name)() needs a string or list, not \(value.typeName)")
+            }
+        }
+
+        // Two-argument helpers are true when the string, or any element of the list, matches
+        func test(_ predicate: (String, String) throws -> Bool) throws -> Value {
+            guard case .string(let pattern) = arguments[1] else {
+                throw failure("the second argument of \(name)() must be a string, not \(arguments[1].typeName)")
+            }
+            switch arguments[0] {
+            case .string(let string): return .bool(try predicate(string, pattern))
+            case .list(let list): return .bool(try list.contains { try predicate($0, pattern) })
+            case .null: return .bool(false)
+            default: throw failure("\(name)() needs a string or list, not \(arguments[0].typeName)")
+            }
+        }
+
+        switch name {
+        case "len":
+            switch arguments[0] {
+            case .string(let string): return .number(Double(string.count))
+            case .list(let list): return .number(Double(list.count))
+            case .null: return .number(0)
+            default: throw failure("len() needs a string or list, not \(arguments[0].typeName)")
+            }
+        case "lower":
+            return try mapStrings(arguments[0]) { $0.lowercased() }
+        case "cleanLabel":
+            return try mapStrings(arguments[0]) { LabelOrder.cleanLabel($0) }
+        case "contains":
+            return try test { $0.range(of: $1, options: .caseInsensitive) != nil }
+        case "startsWith":
+            return try test { $0.lowercased().hasPrefix($1.lowercased()) }
+        case "endsWith":
+            return try test { $0.lowercased().hasSuffix($1.lowercased()) }
+        default:
+            return try test { string, pattern in
+                // Literal patterns were compiled while parsing; others come from contact data
+                guard let regex = regexes[pattern] ?? (try? NSRegularExpression(pattern: pattern)) else {
+                    throw failure("invalid regular expression '\(pattern)' in matches()")
+                }
+                return regex.firstMatch(in: string, range: NSRange(string.startIndex..., in: string)) != nil
+            }
+        }
+    }
+
+    private func failure(_ reason: String) -> ValidationError {
+        ValidationError("Invalid --filter '\(expression)': \(reason)")
+    }
+}
+
+// MARK: - Parsing
+
+extension FilterExpression {
+    private enum Token: Equatable {
+        case identifier(String)
+        case string(String)
+        case number(Double)
+        case symbol(String)
+        case end
+    }
+
+    /// Recursive-descent parser. Unknown fields and functions, and wrong argument counts,
+    /// are reported here, before any contact is fetched.
+    private struct Parser {
+        let expression: String
+        private var tokens: [(token: Token, column: Int)] = []
+        private var position = 0
+
+        init(expression: String) {
+            self.expression = expression
+        }
+
+        mutating func parse() throws -> Node {
+            tokens = try tokenize()
+            let node = try parseOr()
+            guard current == .end else {
+                throw invalid("unexpected \(describe(current))")
+            }
+            return node
+        }
+
+        private var current: Token {
+            tokens[position].token
+        }
+
+        private mutating func advance() {
+            if position < tokens.count - 1 {
+                position += 1
+            }
+        }
+
+        private mutating func accept(_ symbol: String) -> Bool {
+            guard current == .symbol(symbol) else {
+                return false
+            }
+            advance()
+            return true
+        }
+
+        private mutating func expect(_ symbol: String) throws {
+            guard accept(symbol) else {
+                throw invalid("expected '\(symbol)' but found \(describe(current))")
+            }
+        }
+
+        private mutating func parseOr() throws -> Node {
+            var node = try parseAnd()
+            while accept("||") {
+                node = .binary("||", node, try parseAnd())
+            }
+            return node
+        }
+
+        private mutating func parseAnd() throws -> Node {
+            var node = try parseNot()
+            while accept("&&") {
+                node = .binary("&&", node, try parseNot())
+            }
+            return node
+        }
+
+        private mutating func parseNot() throws -> Node {
+            if accept("!") {
+                return .not(try parseNot())
+            }
+            return try parseComparison()
+        }
+
+        private mutating func parseComparison() throws -> Node {
+            let left = try parsePrimary()
+            for op in ["==", "!=", "<=", ">=", "<", ">", "in"] {
+                if accept(op) {
+                    return .binary(op, left, try parsePrimary())
+                }
+            }
+            return left
+        }
+
+        private mutating func parsePrimary() throws -> Node {
+            switch current {
+            case .string(let string):
+                advance()
+                return .literal(.string(string))
+            case .number(let number):
+                advance()
+                return .literal(.number(number))
+            case .symbol("("):
+                advance()
+                let node = try parseOr()
+                try expect(")")
+                return node
+            case .identifier(let name):
+                advance()
+                switch name {
+                case "true": return .literal(.bool(true))
+                case "false": return .literal(.bool(false))
+                case "nil", "null": return .literal(.null)
+                default: break
+                }
+                if accept("(") {
+                    return try parseCall(name)
+                }
+                guard FilterExpression.fields[name] != nil else {
+                    let available = FilterExpression.fields.keys.sorted().joined(separator: ", ")
+                    throw invalid("unknown field '\(name)'. Available fields: \(available)")
+                }
+                return .field(name)
+            default:
+                throw invalid("expected a value but found \(describe(current))")
+            }
+        }
+
+        private mutating func parseCall(_ name: String) throws -> Node {
+            guard let arity = FilterExpression.functions[name] else {
+                let available = FilterExpression.functions.keys.sorted().joined(separator: ", ")
+                throw invalid("unknown function '\(name)'. Available functions: \(available)")
+            }
+            var arguments: [Node] = []
+            if !accept(")") {
+                repeat {
+                    arguments.append(try parseOr())
+                } while accept(",")
+                try expect(")")
+            }
+            guard arguments.count == arity else {
+                throw invalid("\(name)() takes \(arity) argument(s), got \(arguments.count)")
+            }
+            return .call(name, arguments)
+        }
+
+        private func tokenize() throws -> [(token: Token, column: Int)] {
+            var result: [(token: Token, column: Int)] = []
+            let characters = Array(expression)
+            var i = 0
+
+            while i < characters.count {
+                let c = characters[i]
+                let column = i + 1
+                if c.isWhitespace {
+                    i += 1
+                } else if c == "\"" || c == "'" {
+                    var value = ""
+                    i += 1
+                    while i < characters.count && characters[i] != c {
+                        if characters[i] == "\\" && i + 1 < characters.count {
+                            i += 1
+                        }
+                        value.append(characters[i])
+                        i += 1
+                    }
+                    guard i < characters.count else {
+                        throw invalid("unterminated string starting at column \(column)")
+                    }
+                    i += 1
+                    result.append((.string(value), column))
+                } else if c.isNumber {
+                    var text = ""
+                    while i < characters.count && (characters[i].isNumber || characters[i] == ".") {
+                        text.append(characters[i])
+                        i += 1
+                    }
+                    guard let number = Double(text) else {
+                        throw invalid("invalid number '\(text)' at column \(column)")
+                    }
+                    result.append((.number(number), column))
+                } else if c.isLetter || c == "_" {
+                    var name = ""
+                    while i < characters.count && (characters[i].isLetter || characters[i].isNumber || characters[i] == "_") {
+                        name.append(characters[i])
+                        i += 1
+                    }
+                    result.append((name == "in" ? .symbol("in") : .identifier(name), column))
+                } else {
+                    let pair = i + 1 < characters.count ? String([c, characters[i + 1]]) : ""
+                    if ["==", "!=", "<=", ">=", "&&", "||"].contains(pair) {
+                        result.append((.symbol(pair), column))
+                        i += 2
+                    } else if "()<>!,".contains(c) {
+                        result.append((.symbol(String(c)), column))
+                        i += 1
+                    } else if c == "=" {
+                        throw invalid("use '==' to compare, at column \(column)")
+                    } else {
+                        throw invalid("unexpected '\(c)' at column \(column)")
+                    }
+                }
+            }
+            result.append((.end, characters.count + 1))
+            return result
+        }
+
+        private func describe(_ token: Token) -> String {
+            let column = tokens[position].column
+            switch token {
+            case .identifier(let name): return "'\(name)' at column \(column)"
+            case .string(let string): return "\"\(string)\" at column \(column)"
+            case .number(let number): return "\(number) at column \(column)"
+            case .symbol(let symbol): return "'\(symbol)' at column \(column)"
+            case .end: return "end of expression"
+            }
+        }
+
+        private func invalid(_ reason: String) -> ValidationError {
+            ValidationError("Invalid --filter '\(expression)': \(reason)")
+        }
+    }
+}
@@ -0,0 +1,42 @@
+import Foundation
+
+/// Approximate string matching for typo-tolerant search
+enum Fuzzy {
+    /// Levenshtein edit distance between two strings
+    static func distance(_ a: String, _ b: String) -> Int {
+        let a = Array(a)
+        let b = Array(b)
+        if a.isEmpty { return b.count }
+        if b.isEmpty { return a.count }
+
+        var previous = Array(0...b.count)
+        var current = [Int](repeating: 0, count: b.count + 1)
+        for i in 1...a.count {
+            current[0] = i
+            for j in 1...b.count {
+                let cost = a[i - 1] == b[j - 1] ? 0 : 1
+                current[j] = min(previous[j] + 1, current[j - 1] + 1, previous[j - 1] + cost)
+            }
+            swap(&previous, &current)
+        }
+        return previous[b.count]
+    }
+
+    /// Best (lowest) distance between the query and any of the candidates or their words.
+    /// Comparison is case-insensitive.
+    static func score(_ query: String, against candidates: [String]) -> Int? {
+        let query = query.lowercased()
+        var words: [String] = []
+        for candidate in candidates where !candidate.isEmpty {
+            let lower = candidate.lowercased()
+            words.append(lower)
+            words.append(contentsOf: lower.split(separator: " ").map(String.init))
+        }
+        return words.map { distance(query, $0) }.min()
+    }
+
+    /// Largest distance still considered a match for a query of this length
+    static func maxDistance(for query: String) -> Int {
+        max(1, query.count / 3)
+    }
+}
@@ -0,0 +1,27 @@
+import ArgumentParser
+import Foundation
+
+/// How contact IDs are printed (`--id-format`). Apple IDs look like
+/// `ABC123-DEF456:ABPerson`; `uuid` drops the `:ABPerson` suffix for systems that
+/// only want the UUID. The short form still works with `--id`, which accepts prefixes.
+enum IDFormat: String, ExpressibleByArgument, CaseIterable {
+    case full
+    case uuid
+
+    func apply(_ id: String) -> String {
+        switch self {
+        case .full:
+            return id
+        case .uuid:
+            return Self.shortenID(id)
+        }
+    }
+
+    /// The part of an ID before the `:ABPerson` (or other) suffix
+    static func shortenID(_ id: String) -> String {
+        guard let colon = id.firstIndex(of: ":") else {
+            return id
+        }
+        return String(id[..<colon])
+    }
+}
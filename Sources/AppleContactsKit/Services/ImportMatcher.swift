@@ -0,0 +1,104 @@
+import Contacts
+import Foundation
+
+/// Finds the existing contact an imported contact duplicates: by email (case-insensitive),
+/// then by phone (normalized), then by full name when the import has no email or phone
+struct ImportMatcher {
+    private var byEmail: [String: CNContact] = [:]
+    private var byPhone: [String: CNContact] = [:]
+    private var byName: [String: CNContact] = [:]
+    /// The version of each contact the keys above were taken from
+    private var byID: [String: CNContact] = [:]
+    private let region: String?
+
+    /// Existing contacts must be fetched with `ContactsService.fullKeys`
+    init(existing: [CNContact], region: String?) {
+        self.region = region
+        for contact in existing {
+            insert(contact)
+        }
+    }
+
+    /// Make a contact saved during the import matchable, so later cards are compared with
+    /// what is now stored rather than with the snapshot taken at the start. A contact that
+    /// is already known (an updated one) replaces its earlier version.
+    mutating func insert(_ contact: CNContact) {
+        if let previous = byID[contact.identifier] {
+            remove(previous)
+        }
+        byID[contact.identifier] = contact
+
+        for key in emailKeys(of: contact) where byEmail[key] == nil {
+            byEmail[key] = contact
+        }
+        for key in phoneKeys(of: contact) where byPhone[key] == nil {
+            byPhone[key] = contact
+        }
+        if let key = nameKey(of: contact), byName[key] == nil {
+            byName[key] = contact
+        }
+    }
+
+    /// Drop the entries that point at this version of a contact
+    private mutating func remove(_ contact: CNContact) {
+        let id = contact.identifier
+        for key in emailKeys(of: contact) where byEmail[key]?.identifier == id {
+            byEmail[key] = nil
+        }
+        for key in phoneKeys(of: contact) where byPhone[key]?.identifier == id {
+            byPhone[key] = nil
+        }
+        if let key = nameKey(of: contact), byName[key]?.identifier == id {
+            byName[key] = nil
+        }
+    }
+
+    private func emailKeys(of contact: CNContact) -> [String] {
+        contact.emailAddresses.map { Self.emailKey($0.value as String) }.filter { !$0.isEmpty }
+    }
+
+    private func phoneKeys(of contact: CNContact) -> [String] {
+        contact.phoneNumbers.map { PhoneNumbers.normalize($0.value.stringValue, region: region) }.filter { !$0.isEmpty }
+    }
+
+    private func nameKey(of contact: CNContact) -> String? {
+        let name = contact.fullName.lowercased()
+        return name.isEmpty ? nil : name
+    }
+
+    /// The matching contact and a short description of what matched, or nil.
+    /// With `alwaysCompareNames`, the full name is checked even when the contact has an
+    /// email or phone, which is stricter than imports need but right for a single new contact.
+    func match(_ contact: CNContact, alwaysCompareNames: Bool = false) -> (contact: CNContact, reason: String)? {
+        for email in contact.emailAddresses {
+            if let existing = byEmail[Self.emailKey(email.value as String)] {
+                return (existing, "email \(email.value)")
+            }
+        }
+
+        for phone in contact.phoneNumbers {
+            let key = PhoneNumbers.normalize(phone.value.stringValue, region: region)
+            if !key.isEmpty, let existing = byPhone[key] {
+                return (existing, "phone \(phone.value.stringValue)")
+            }
+        }
+
+        if alwaysCompareNames || (contact.emailAddresses.isEmpty && contact.phoneNumbers.isEmpty),
+           let existing = byName[contact.fullName.lowercased()]
+        {
+            return (existing, "name")
+        }
+
+        return nil
+    }
+
+    /// An existing contact with the same full name (case-insensitive), whatever else differs
+    func sameName(_ contact: CNContact) -> CNContact? {
+        let name = contact.fullName.lowercased()
+        return name.isEmpty ? nil : byName[name]
+    }
+
+    private static func emailKey(_ email: String) -> String {
+        email.trimmingCharacters(in: .whitespaces).lowercased()
+    }
+}
@@ -0,0 +1,117 @@
+import ArgumentParser
+import Foundation
+
+/// A small JSONPath subset for `--jsonpath`: `$`, `.key`, `['key']`, `[0]`, `[-1]`,
+/// and `*` / `[*]` for every element. Recursive descent and filters aren't supported.
+struct JSONPath {
+    enum Component: Equatable {
+        case key(String)
+        case index(Int)
+        case wildcard
+    }
+
+    let expression: String
+    let components: [Component]
+
+    init(parsing expression: String) throws {
+        self.expression = expression
+        var components: [Component] = []
+        var rest = Substring(expression.trimmingCharacters(in: .whitespaces))
+
+        func invalid(_ reason: String) -> ValidationError {
+            ValidationError("Invalid --jsonpath '\(expression)': \(reason)")
+        }
+
+        guard rest.first == "$" else {
+            throw invalid("must start with $")
+        }
+        rest = rest.dropFirst()
+
+        while let first = rest.first {
+            if first == "." {
+                rest = rest.dropFirst()
+                if rest.first == "*" {
+                    components.append(.wildcard)
+                    rest = rest.dropFirst()
+                    continue
+                }
+                let name = rest.prefix { $0.isLetter || $0.isNumber || $0 == "_" || $0 == "-" }
+                guard !name.isEmpty else {
+                    throw invalid(rest.first == "." ? "recursive descent (..) is not supported" : "expected a name after '.'")
+                }
+                components.append(.key(String(name)))
+                rest = rest.dropFirst(name.count)
+            } else if first == "[" {
+                guard let close = rest.firstIndex(of: "]") else {
+                    throw invalid("unclosed '['")
+                }
+                let inner = rest[rest.index(after: rest.startIndex)..<close].trimmingCharacters(in: .whitespaces)
+                if inner == "*" {
+                    components.append(.wildcard)
+                } else if let index = Int(inner) {
+                    components.append(.index(index))
+                } else if inner.count >= 2, let quote = inner.first, quote == "'" || quote == "\"", inner.last == quote {
+                    components.append(.key(String(inner.dropFirst().dropLast())))
+                } else {
+                    throw invalid("expected an index, * or a quoted key in [\(inner)]")
+                }
+                rest = rest[rest.index(after: close)...]
+            } else {
+                throw invalid("unexpected '\(first)'")
+            }
+        }
+        self.components = components
+    }
+
+    /// Every value the path selects in `root`
+    func evaluate(_ root: Any) -> [Any] {
+        components.reduce([root]) { values, component in
+            values.flatMap { value -> [Any] in
+                switch component {
+                case .key(let key):
+                    return ((value as? [String: Any])?[key]).map { [$0] } ?? []
+                case .index(let index):
+                    guard let array = value as? [Any] else { return [] }
+                    let resolved = index < 0 ? array.count + index : index
+                    return array.indices.contains(resolved) ? [array[resolved]] : []
+                case .wildcard:
+                    if let array = value as? [Any] {
+                        return array
+                    }
+                    if let object = value as? [String: Any] {
+                        return object.keys.sorted().compactMap { object[$0] }
+                    }
+                    return []
+                }
+            }
+        }
+    }
+
+    /// Selected values, one per line: strings and numbers as-is, objects and arrays as JSON.
+    /// Throws when the path matches nothing.
+    func lines(in root: Any) throws -> [String] {
+        let matches = evaluate(root)
+        guard !matches.isEmpty else {
+            throw ContactsError.jsonPathNoMatch(expression)
+        }
+        return matches.map(Self.text)
+    }
+
+    private static func text(_ value: Any) -> String {
+        switch value {
+        case let string as String:
+            return string
+        case is NSNull:
+            return "null"
+        case let number as NSNumber:
+            return CFGetTypeID(number) == CFBooleanGetTypeID() ? (number.boolValue ? "true" : "false") : number.stringValue
+        default:
+            guard JSONSerialization.isValidJSONObject(value),
+                  let data = try? JSONSerialization.data(withJSONObject: value, options: [.prettyPrinted, .sortedKeys])
+            else {
+                return String(describing: value)
+            }
+            return String(decoding: data, as: UTF8.self)
+        }
+    }
+}
@@ -0,0 +1,102 @@
+import Contacts
+import Foundation
+
+/// Ordering of labeled values (phones, emails) so the preferred one comes first
+enum LabelOrder {
+    /// Used when neither --label-order nor the config file sets an order
+    static let defaultOrder = ["main", "mobile", "iphone", "work", "home", "school", "other"]
+
+    /// Whether a label is one the user typed in (e.g. "Summer House") rather than one of
+    /// Apple's standard labels, which are stored wrapped as `_$!<Home>!$_`
+    static func isCustomLabel(_ label: String?) -> Bool {
+        guard let label, !label.isEmpty else { return false }
+        return !(label.hasPrefix("_$!<") && label.hasSuffix(">!$_"))
+    }
+
+    /// Label for display: standard labels localized the way Contacts.app shows them
+    /// ("home", "mobile"), custom labels exactly as the user typed them. No label reads "other".
+    static func displayLabel(_ label: String?) -> String {
+        guard let label, !label.isEmpty else {
+            return CNLabeledValue<NSString>.localizedString(forLabel: CNLabelOther)
+        }
+        if isCustomLabel(label) {
+            return label
+        }
+        let localized = CNLabeledValue<NSString>.localizedString(forLabel: label)
+        // Wrapped labels Contacts doesn't know come back unchanged; show them unwrapped
+        if localized == label {
+            return cleanLabel(label)
+        }
+        return localized
+    }
+
+    /// Label without Apple's `_$!<...>!$_` wrapper, lowercased (e.g. "work").
+    /// For comparing and sorting; use `displayLabel` to show a label.
+    static func cleanLabel(_ label: String?) -> String {
+        guard var label else { return "" }
+        if label.hasPrefix("_$!<") && label.hasSuffix(">!$_") {
+            label = String(label.dropFirst(4).dropLast(4))
+        }
+        return label.lowercased()
+    }
+
+    /// Stable sort by position of each value's label in `order`;
+    /// labels not in the list keep their relative order after the listed ones
+    static func sortLabeledByPreference<T: NSCopying & NSSecureCoding>(_ values: [CNLabeledValue<T>], order: [String]) -> [CNLabeledValue<T>] {
+        let rank = Dictionary(order.map { $0.lowercased() }.enumerated().map { ($1, $0) }, uniquingKeysWith: { first, _ in first })
+        return values.enumerated()
+            .sorted { a, b in
+                let ra = rank[cleanLabel(a.element.label)] ?? order.count
+                let rb = rank[cleanLabel(b.element.label)] ?? order.count
+                return ra != rb ? ra < rb : a.offset < b.offset
+            }
+            .map { $0.element }
+    }
+
+    /// Sort by (label, value) so the order doesn't depend on how Contacts returned them
+    static func sortLabeledStable<T: NSCopying & NSSecureCoding>(_ values: [CNLabeledValue<T>], text: (T) -> String) -> [CNLabeledValue<T>] {
+        values
+            .map { (key: (cleanLabel($0.label), text($0.value)), value: $0) }
+            .sorted { $0.key < $1.key }
+            .map(\.value)
+    }
+}
+
+extension CNContact {
+    /// Copy with phones and emails sorted by label preference
+    /// Requires the phone and email keys
+    func withLabelOrder(_ order: [String]) -> CNContact {
+        let mutable = mutableCopy() as! CNMutableContact
+        if isKeyAvailable(CNContactPhoneNumbersKey) {
+            mutable.phoneNumbers = LabelOrder.sortLabeledByPreference(phoneNumbers, order: order)
+        }
+        if isKeyAvailable(CNContactEmailAddressesKey) {
+            mutable.emailAddresses = LabelOrder.sortLabeledByPreference(emailAddresses, order: order)
+        }
+        return mutable.copy() as! CNContact
+    }
+
+    /// Copy with phones, emails and addresses sorted by (label, value), or the
+    /// contact itself if they are already in that order
+    func withStableOrder() -> CNContact {
+        let phones = isKeyAvailable(CNContactPhoneNumbersKey)
+            ? LabelOrder.sortLabeledStable(phoneNumbers) { $0.stringValue } : nil
+        let emails = isKeyAvailable(CNContactEmailAddressesKey)
+            ? LabelOrder.sortLabeledStable(emailAddresses) { $0 as String } : nil
+        let addresses = isKeyAvailable(CNContactPostalAddressesKey)
+            ? LabelOrder.sortLabeledStable(postalAddresses) { CNPostalAddressFormatter.string(from: $0, style: .mailingAddress) } : nil
+
+        func reordered<T: NSCopying & NSSecureCoding>(_ sorted: [CNLabeledValue<T>]?, _ original: @autoclosure () -> [CNLabeledValue<T>]) -> Bool {
+            sorted.map { $0.map(\.identifier) != original().map(\.identifier) } ?? false
+        }
+        guard reordered(phones, phoneNumbers) || reordered(emails, emailAddresses) || reordered(addresses, postalAddresses) else {
+            return self
+        }
+
+        let mutable = mutableCopy() as! CNMutableContact
+        if let phones { mutable.phoneNumbers = phones }
+        if let emails { mutable.emailAddresses = emails }
+        if let addresses { mutable.postalAddresses = addresses }
+        return mutable.copy() as! CNContact
+    }
+}
@@ -0,0 +1,66 @@
+import Contacts
+import Foundation
+
+/// A data-quality problem found in a contact
+struct LintIssue {
+    let contactID: String
+    let contactName: String
+    let field: String
+    let value: String
+    let message: String
+    /// Corrected value, if a confident fix is known
+    let suggestion: String?
+
+    var isFixable: Bool { suggestion != nil }
+}
+
+/// Data-quality checks run by the `lint` command
+enum Linter {
+    /// Keys needed by all checks
+    static var requiredKeys: [CNKeyDescriptor] {
+        ContactsService.basicKeys + [
+            CNContactEmailAddressesKey as CNKeyDescriptor,
+            CNContactMiddleNameKey as CNKeyDescriptor,
+            CNContactDepartmentNameKey as CNKeyDescriptor,
+            CNContactJobTitleKey as CNKeyDescriptor,
+        ]
+    }
+
+    /// Run every check against a contact
+    static func issues(for contact: CNContact) -> [LintIssue] {
+        emailIssues(for: contact) + encodingIssues(for: contact)
+    }
+
+    /// Double-encoded UTF-8 in text fields
+    static func encodingIssues(for contact: CNContact) -> [LintIssue] {
+        CNContact.textFields.compactMap { field in
+            guard contact.isKeyAvailable(field.key) else { return nil }
+            let value = contact[keyPath: field.read]
+            guard let fixed = Mojibake.fix(value) else { return nil }
+            return LintIssue(
+                contactID: contact.identifier,
+                contactName: contact.fullName,
+                field: field.name,
+                value: value,
+                message: "likely mis-encoded text",
+                suggestion: fixed
+            )
+        }
+    }
+
+    /// Malformed or misspelled email addresses
+    static func emailIssues(for contact: CNContact) -> [LintIssue] {
+        contact.emailAddresses.compactMap { email in
+            let address = email.value as String
+            guard let problem = EmailSuggestions.problem(with: address) else { return nil }
+            return LintIssue(
+                contactID: contact.identifier,
+                contactName: contact.fullName,
+                field: "email",
+                value: address,
+                message: problem,
+                suggestion: EmailSuggestions.suggestFix(for: address)
+            )
+        }
+    }
+}
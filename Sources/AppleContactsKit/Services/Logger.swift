@@ -0,0 +1,77 @@
+import Foundation
+
+/// Diagnostic output on stderr, as human-readable text or JSON lines
+struct Logger {
+    enum Level: String {
+        case debug
+        case info
+        case warning
+        case error
+    }
+
+    let command: String
+    /// Emit JSON lines instead of text
+    let json: Bool
+    /// Include debug messages and timing
+    let verbose: Bool
+    private let start = Date()
+
+    init(command: String, json: Bool, verbose: Bool) {
+        self.command = command
+        self.json = json
+        self.verbose = verbose
+    }
+
+    /// Milliseconds since the logger was created
+    var elapsedMilliseconds: Int {
+        Int(Date().timeIntervalSince(start) * 1000)
+    }
+
+    func debug(_ message: String, _ fields: [String: Any] = [:]) {
+        if verbose {
+            write(.debug, message, fields)
+        }
+    }
+
+    func info(_ message: String, _ fields: [String: Any] = [:]) {
+        if verbose || json {
+            write(.info, message, fields)
+        }
+    }
+
+    func warning(_ message: String, _ fields: [String: Any] = [:]) {
+        write(.warning, message, fields)
+    }
+
+    func error(_ message: String, _ fields: [String: Any] = [:]) {
+        write(.error, message, fields)
+    }
+
+    /// Log command completion with its total duration
+    func finish() {
+        info("done", ["duration_ms": elapsedMilliseconds])
+    }
+
+    private func write(_ level: Level, _ message: String, _ fields: [String: Any]) {
+        let line: String
+        if json {
+            var entry = fields
+            entry["time"] = ISO8601DateFormatter().string(from: Date())
+            entry["level"] = level.rawValue
+            entry["msg"] = message
+            entry["command"] = command
+            guard let data = try? JSONSerialization.data(withJSONObject: entry, options: .sortedKeys),
+                  let encoded = String(data: data, encoding: .utf8)
+            else {
+                return
+            }
+            line = encoded
+        } else {
+            let details = fields.keys.sorted().map { "\($0)=\(fields[$0]!)" }
+            line = ([level == .info ? "" : "\(level.rawValue):", message] + details)
+                .filter { !$0.isEmpty }
+                .joined(separator: " ")
+        }
+        FileHandle.standardError.write(Data((line + "\n").utf8))
+    }
+}
@@ -0,0 +1,44 @@
+import Contacts
+import Foundation
+
+/// Opening postal addresses in Apple Maps
+enum Maps {
+    /// The address on one line, e.g. "Storgata 1, 0155 Oslo, Norway"; empty if it has no parts
+    static func singleLine(_ address: CNPostalAddress) -> String {
+        CNPostalAddressFormatter.string(from: address, style: .mailingAddress)
+            .split(whereSeparator: \.isNewline)
+            .map { $0.trimmingCharacters(in: .whitespaces) }
+            .filter { !$0.isEmpty }
+            .joined(separator: ", ")
+    }
+
+    /// A maps:// URL searching for the address, or nil if the address is empty
+    static func url(for address: CNPostalAddress) -> URL? {
+        let query = singleLine(address)
+        guard !query.isEmpty else {
+            return nil
+        }
+        var components = URLComponents()
+        components.scheme = "maps"
+        components.host = ""
+        components.queryItems = [URLQueryItem(name: "q", value: query)]
+        return components.url
+    }
+
+    /// Open the address in Maps with `open`
+    static func open(_ address: CNPostalAddress) throws {
+        guard let url = url(for: address) else {
+            throw ContactsError.noAddress
+        }
+
+        let process = Process()
+        process.executableURL = URL(fileURLWithPath: "/usr/bin/open")
+        process.arguments = [url.absoluteString]
+        try process.run()
+        process.waitUntilExit()
+
+        guard process.terminationStatus == 0 else {
+            throw ContactsError.mapsFailed(status: process.terminationStatus)
+        }
+    }
+}
@@ -0,0 +1,77 @@
+import Contacts
+import Foundation
+
+/// MeCard, the compact single-line contact format used by Japanese carriers and many QR apps:
+/// `MECARD:N:Fisher,Erik;TEL:+4790000000;EMAIL:erik@example.com;;`
+enum MeCard {
+    /// Encode a contact. Fields whose keys weren't fetched are left out.
+    static func encode(_ contact: CNContact) -> String {
+        var fields: [(String, String)] = []
+
+        // Company cards have no person name; use the organization instead
+        if contact.givenName.isEmpty && contact.familyName.isEmpty {
+            fields.append(("N", escape(contact.displayName)))
+        } else if contact.givenName.isEmpty || contact.familyName.isEmpty {
+            fields.append(("N", escape(contact.familyName + contact.givenName)))
+        } else {
+            fields.append(("N", "\(escape(contact.familyName)),\(escape(contact.givenName))"))
+        }
+
+        if contact.isKeyAvailable(CNContactPhoneticGivenNameKey),
+           contact.isKeyAvailable(CNContactPhoneticFamilyNameKey),
+           !contact.phoneticGivenName.isEmpty || !contact.phoneticFamilyName.isEmpty
+        {
+            let sound = [contact.phoneticFamilyName, contact.phoneticGivenName]
+                .filter { !$0.isEmpty }
+                .map(escape)
+                .joined(separator: ",")
+            fields.append(("SOUND", sound))
+        }
+
+        if !contact.nickname.isEmpty {
+            fields.append(("NICKNAME", escape(contact.nickname)))
+        }
+        if contact.isKeyAvailable(CNContactPhoneNumbersKey) {
+            fields += contact.phoneNumbers.map { ("TEL", escape(PhoneNumbers.digits($0.value.stringValue))) }
+        }
+        if contact.isKeyAvailable(CNContactEmailAddressesKey) {
+            fields += contact.emailAddresses.map { ("EMAIL", escape($0.value as String)) }
+        }
+
+        // BDAY is YYYYMMDD, so birthdays without a year are left out
+        if contact.isKeyAvailable(CNContactBirthdayKey),
+           let birthday = contact.birthday,
+           let year = birthday.year, let month = birthday.month, let day = birthday.day
+        {
+            fields.append(("BDAY", String(format: "%04d%02d%02d", year, month, day)))
+        }
+
+        if contact.isKeyAvailable(CNContactPostalAddressesKey), let address = contact.postalAddresses.first {
+            let formatted = CNPostalAddressFormatter.string(from: address.value, style: .mailingAddress)
+                .replacingOccurrences(of: "\n", with: ", ")
+            fields.append(("ADR", escape(formatted)))
+        }
+        if contact.isKeyAvailable(CNContactUrlAddressesKey) {
+            fields += contact.urlAddresses.map { ("URL", escape($0.value as String)) }
+        }
+
+        // ORG isn't in the original spec but most readers accept it
+        if !contact.organizationName.isEmpty && !(contact.givenName.isEmpty && contact.familyName.isEmpty) {
+            fields.append(("ORG", escape(contact.organizationName)))
+        }
+
+        return "MECARD:" + fields.map { "\($0.0):\($0.1);" }.joined() + ";"
+    }
+
+    /// Backslash-escape the characters MeCard uses as delimiters
+    static func escape(_ value: String) -> String {
+        var result = ""
+        for character in value {
+            if "\\;:,".contains(character) {
+                result.append("\\")
+            }
+            result.append(character)
+        }
+        return result
+    }
+}
@@ -0,0 +1,34 @@
+import Contacts
+import Foundation
+
+/// Table of which contacts belong to which groups
+struct MembershipMatrix {
+    struct Row {
+        let contact: CNContact
+        /// Membership flags, in the same order as `groups`
+        let memberships: [Bool]
+    }
+
+    let groups: [CNGroup]
+    let rows: [Row]
+
+    /// Build the matrix from each group's member IDs (keyed by group identifier)
+    init(groups: [CNGroup], contacts: [CNContact], memberIDs: [String: Set<String>]) {
+        self.groups = groups
+        self.rows = contacts.map { contact in
+            Row(
+                contact: contact,
+                memberships: groups.map { memberIDs[$0.identifier]?.contains(contact.identifier) ?? false }
+            )
+        }
+    }
+
+    /// Render as CSV with one row per contact and one column per group
+    func csv() -> String {
+        var lines = [CSV.row(["Name", "ID"] + groups.map(\.name))]
+        for row in rows {
+            lines.append(CSV.row([row.contact.fullName, row.contact.identifier] + row.memberships.map { $0 ? "X" : "" }))
+        }
+        return lines.joined(separator: "\n")
+    }
+}
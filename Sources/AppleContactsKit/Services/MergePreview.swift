@@ -0,0 +1,107 @@
+import Contacts
+import Foundation
+
+/// Where a value in a merge preview came from
+struct MergeNote {
+    enum Source: String {
+        case into
+        case from
+    }
+
+    let field: String
+    let value: String
+    let source: Source
+    /// True when the value is dropped because the target already has a different one
+    let discarded: Bool
+}
+
+/// Computes the result of merging one contact into another without saving anything
+enum MergePreview {
+    /// Union of both contacts: scalar fields keep the target's value when set,
+    /// multi-valued fields combine both sides without duplicates.
+    /// Both contacts must be fetched with `ContactsService.fullKeys`.
+    static func preview(into: CNContact, from: CNContact) -> (contact: CNMutableContact, notes: [MergeNote]) {
+        let merged = into.mutableCopy() as! CNMutableContact
+        var notes: [MergeNote] = []
+
+        func scalar(_ field: String, _ target: String, _ source: String, assign: (String) -> Void) {
+            if !target.isEmpty {
+                notes.append(MergeNote(field: field, value: target, source: .into, discarded: false))
+                if !source.isEmpty && source != target {
+                    notes.append(MergeNote(field: field, value: source, source: .from, discarded: true))
+                }
+            } else if !source.isEmpty {
+                assign(source)
+                notes.append(MergeNote(field: field, value: source, source: .from, discarded: false))
+            }
+        }
+
+        scalar("firstName", into.givenName, from.givenName) { merged.givenName = $0 }
+        scalar("middleName", into.middleName, from.middleName) { merged.middleName = $0 }
+        scalar("lastName", into.familyName, from.familyName) { merged.familyName = $0 }
+        scalar("nickname", into.nickname, from.nickname) { merged.nickname = $0 }
+        scalar("organization", into.organizationName, from.organizationName) { merged.organizationName = $0 }
+        scalar("department", into.departmentName, from.departmentName) { merged.departmentName = $0 }
+        scalar("jobTitle", into.jobTitle, from.jobTitle) { merged.jobTitle = $0 }
+        scalar("birthday", into.birthdayString ?? "", from.birthdayString ?? "") { _ in merged.birthday = from.birthday }
+
+        func union<T: NSCopying & NSSecureCoding>(
+            _ field: String,
+            _ target: [CNLabeledValue<T>],
+            _ source: [CNLabeledValue<T>],
+            key: (T) -> String,
+            display: (CNLabeledValue<T>) -> String
+        ) -> [CNLabeledValue<T>] {
+            var seen = Set<String>()
+            var result: [CNLabeledValue<T>] = []
+            for (values, origin) in [(target, MergeNote.Source.into), (source, .from)] {
+                for item in values where seen.insert(key(item.value)).inserted {
+                    result.append(item)
+                    notes.append(MergeNote(field: field, value: display(item), source: origin, discarded: false))
+                }
+            }
+            return result
+        }
+
+        func labeled<T: NSCopying & NSSecureCoding>(_ value: CNLabeledValue<T>, _ text: String) -> String {
+            let label = LabelOrder.displayLabel(value.label)
+            return "\(text) (\(label))"
+        }
+
+        merged.phoneNumbers = union(
+            "phone", into.phoneNumbers, from.phoneNumbers,
+            key: { PhoneNumbers.digits($0.stringValue) },
+            display: { labeled($0, $0.value.stringValue) }
+        )
+        merged.emailAddresses = union(
+            "email", into.emailAddresses, from.emailAddresses,
+            key: { ($0 as String).lowercased() },
+            display: { labeled($0, $0.value as String) }
+        )
+        merged.postalAddresses = union(
+            "address", into.postalAddresses, from.postalAddresses,
+            key: { CNPostalAddressFormatter.string(from: $0, style: .mailingAddress).lowercased() },
+            display: {
+                labeled($0, CNPostalAddressFormatter.string(from: $0.value, style: .mailingAddress)
+                    .replacingOccurrences(of: "\n", with: ", "))
+            }
+        )
+        merged.urlAddresses = union(
+            "url", into.urlAddresses, from.urlAddresses,
+            key: { ($0 as String).lowercased() },
+            display: { labeled($0, $0.value as String) }
+        )
+        merged.socialProfiles = union(
+            "social", into.socialProfiles, from.socialProfiles,
+            key: { "\($0.service.lowercased()):\($0.username.lowercased())" },
+            display: { "\($0.value.username) (\($0.value.service))" }
+        )
+        merged.contactRelations = union(
+            "relation", into.contactRelations, from.contactRelations,
+            key: { $0.name.lowercased() },
+            display: { labeled($0, $0.value.name) }
+        )
+
+        return (merged, notes)
+    }
+}
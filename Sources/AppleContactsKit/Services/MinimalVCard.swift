@@ -0,0 +1,125 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+/// Builds small vCard 3.0 cards containing only selected properties (`export --only`),
+/// instead of Apple's full card
+enum MinimalVCard {
+    enum Field: String, CaseIterable {
+        case name
+        case nickname
+        case org
+        case title
+        /// First phone number only
+        case phone
+        case phones
+        /// First email address only
+        case email
+        case emails
+        case birthday
+        case address
+        case url
+    }
+
+    /// Parse a comma-separated field list, throwing on unknown names
+    static func parse(_ spec: String) throws -> [Field] {
+        try spec.split(separator: ",").map { raw in
+            let name = raw.trimmingCharacters(in: .whitespaces).lowercased()
+            guard let field = Field(rawValue: name) else {
+                let available = Field.allCases.map(\.rawValue).joined(separator: ", ")
+                throw ValidationError("Unknown --only field '\(name)'. Available: \(available)")
+            }
+            return field
+        }
+    }
+
+    /// Build a card with the requested fields. N and FN are always included because
+    /// vCard 3.0 requires them. Lines end in CRLF and are folded at 75 octets.
+    static func build(_ contact: CNContact, fields: [Field]) -> String {
+        let selected = Set(fields)
+        var lines = ["BEGIN:VCARD", "VERSION:3.0"]
+
+        let name = [contact.familyName, contact.givenName, contact.middleName, "", ""].map(escape).joined(separator: ";")
+        lines.append("N:\(name)")
+        lines.append("FN:\(escape(contact.displayName))")
+
+        if selected.contains(.nickname), !contact.nickname.isEmpty {
+            lines.append("NICKNAME:\(escape(contact.nickname))")
+        }
+        if selected.contains(.org), !contact.organizationName.isEmpty {
+            lines.append("ORG:\(escape(contact.organizationName))")
+        }
+        if selected.contains(.title), contact.isKeyAvailable(CNContactJobTitleKey), !contact.jobTitle.isEmpty {
+            lines.append("TITLE:\(escape(contact.jobTitle))")
+        }
+
+        if contact.isKeyAvailable(CNContactPhoneNumbersKey) {
+            let phones = selected.contains(.phones) ? contact.phoneNumbers
+                : selected.contains(.phone) ? Array(contact.phoneNumbers.prefix(1)) : []
+            for phone in phones {
+                lines.append("TEL\(typeParameter(phone.label)):\(escape(phone.value.stringValue))")
+            }
+        }
+
+        if contact.isKeyAvailable(CNContactEmailAddressesKey) {
+            let emails = selected.contains(.emails) ? contact.emailAddresses
+                : selected.contains(.email) ? Array(contact.emailAddresses.prefix(1)) : []
+            for email in emails {
+                lines.append("EMAIL;TYPE=INTERNET\(typeParameter(email.label, separator: ",")):\(escape(email.value as String))")
+            }
+        }
+
+        if selected.contains(.birthday), contact.isKeyAvailable(CNContactBirthdayKey),
+           let birthday = contact.birthday, let month = birthday.month, let day = birthday.day
+        {
+            if let year = birthday.year {
+                lines.append(String(format: "BDAY:%04d-%02d-%02d", year, month, day))
+            } else {
+                // vCard 3.0 has no yearless dates; use Apple's placeholder year
+                lines.append(String(format: "BDAY;X-APPLE-OMIT-YEAR=1604:1604-%02d-%02d", month, day))
+            }
+        }
+
+        if selected.contains(.address), contact.isKeyAvailable(CNContactPostalAddressesKey) {
+            for address in contact.postalAddresses {
+                let value = address.value
+                let parts = ["", "", value.street, value.city, value.state, value.postalCode, value.country]
+                lines.append("ADR\(typeParameter(address.label)):\(parts.map(escape).joined(separator: ";"))")
+            }
+        }
+
+        if selected.contains(.url), contact.isKeyAvailable(CNContactUrlAddressesKey) {
+            for url in contact.urlAddresses {
+                lines.append("URL:\(escape(url.value as String))")
+            }
+        }
+
+        lines.append("END:VCARD")
+        return lines.map { VCard.foldLine($0, width: 75) }.joined(separator: "\r\n") + "\r\n"
+    }
+
+    /// vCard TYPE parameter for a Contacts label, or "" for custom labels
+    private static func typeParameter(_ label: String?, separator: String = ";TYPE=") -> String {
+        let type: String?
+        switch label ?? "" {
+        case CNLabelHome: type = "HOME"
+        case CNLabelWork: type = "WORK"
+        case CNLabelPhoneNumberMobile, CNLabelPhoneNumberiPhone: type = "CELL"
+        case CNLabelPhoneNumberMain: type = "MAIN"
+        case CNLabelPhoneNumberHomeFax, CNLabelPhoneNumberWorkFax, CNLabelPhoneNumberOtherFax: type = "FAX"
+        case CNLabelPhoneNumberPager: type = "PAGER"
+        default: type = nil
+        }
+        return type.map { separator + $0 } ?? ""
+    }
+
+    /// Escape a text value (RFC 2426 section 4)
+    static func escape(_ value: String) -> String {
+        value
+            .replacingOccurrences(of: "\\", with: "\\\\")
+            .replacingOccurrences(of: ",", with: "\\,")
+            .replacingOccurrences(of: ";", with: "\\;")
+            .replacingOccurrences(of: "\r\n", with: "\\n")
+            .replacingOccurrences(of: "\n", with: "\\n")
+    }
+}
@@ -0,0 +1,64 @@
+import AddressBook
+import Foundation
+
+/// When contacts were last changed (and added). The Contacts framework doesn't expose
+/// this, but the older AddressBook framework does, for the same records and identifiers.
+enum ModificationDates {
+    /// Modification date for every contact, keyed by contact identifier
+    static func all() throws -> [String: Date] {
+        try dates(kABModificationDateProperty)
+    }
+
+    /// Creation date for every contact, keyed by contact identifier.
+    /// Contacts the address book has no creation date for are left out.
+    static func created() throws -> [String: Date] {
+        try dates(kABCreationDateProperty)
+    }
+
+    private static func dates(_ property: String) throws -> [String: Date] {
+        guard let book = ABAddressBook.shared(), let people = book.people() else {
+            throw ContactsError.modificationDatesUnavailable
+        }
+
+        var dates: [String: Date] = [:]
+        for case let person as ABPerson in people {
+            if let id = person.uniqueId, let date = person.value(forProperty: property) as? Date {
+                dates[id] = date
+            }
+        }
+        return dates
+    }
+
+    /// Modification date of one contact, or nil if the address book has none for it
+    static func date(for id: String) throws -> Date? {
+        guard let book = ABAddressBook.shared() else {
+            throw ContactsError.modificationDatesUnavailable
+        }
+        let person = book.record(forUniqueId: id) as? ABPerson
+        return person?.value(forProperty: kABModificationDateProperty) as? Date
+    }
+
+    /// Units of days and longer, for durations such as --stale-after where "6m" is far more
+    /// likely meant as six months than six minutes
+    static let longUnits: Set<String> = ["d", "w", "mo", "y"]
+
+    /// Parse a duration like "30m", "12h", "7d", "2w", "6mo" or "2y" into seconds.
+    /// "m" is minutes; months ("mo") count as 30 days and years as 365.
+    /// With `allowedUnits`, other units are rejected.
+    static func interval(from spec: String, allowedUnits: Set<String>? = nil) -> TimeInterval? {
+        let units: [String: TimeInterval] = [
+            "m": 60, "h": 3600, "d": 86400, "w": 604_800, "mo": 2_592_000, "y": 31_536_000,
+        ]
+        let lower = spec.lowercased()
+        let unitName = lower.hasSuffix("mo") ? "mo" : String(lower.suffix(1))
+        if let allowedUnits, !allowedUnits.contains(unitName) {
+            return nil
+        }
+        guard let unit = units[unitName],
+              let amount = Double(lower.dropLast(unitName.count)), amount >= 0
+        else {
+            return nil
+        }
+        return amount * unit
+    }
+}
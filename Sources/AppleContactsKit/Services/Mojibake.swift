@@ -0,0 +1,28 @@
+import Foundation
+
+/// Detection and repair of double-encoded UTF-8 ("JosÃ©" instead of "José")
+enum Mojibake {
+    /// Undo a UTF-8 → Latin-1/Windows-1252 → UTF-8 double encoding.
+    /// Returns nil when the string doesn't decode as double-encoded text.
+    static func fix(_ text: String) -> String? {
+        // Double-encoded text contains a UTF-8 lead byte (0xC2-0xF4) read as a Latin-1 character
+        guard text.unicodeScalars.contains(where: { (0xC2...0xF4).contains($0.value) }) else {
+            return nil
+        }
+
+        for encoding in [String.Encoding.windowsCP1252, .isoLatin1] {
+            if let bytes = text.data(using: encoding),
+               let decoded = String(data: bytes, encoding: .utf8),
+               decoded != text
+            {
+                return decoded
+            }
+        }
+        return nil
+    }
+
+    /// Whether the text looks like double-encoded UTF-8
+    static func looksLikeMojibake(_ text: String) -> Bool {
+        fix(text) != nil
+    }
+}
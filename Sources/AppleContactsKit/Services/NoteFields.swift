@@ -0,0 +1,26 @@
+import Foundation
+
+/// Structured data kept in contact notes as `key: value` lines, e.g.
+/// "twitter: @erik\nbudget: 5000", for attributes Contacts has no field for
+enum NoteFields {
+    /// Parse `key: value` lines. Keys are lowercased; the first value for a key wins.
+    /// Lines without a key, and URLs like "https://...", are ignored.
+    static func parse(_ note: String) -> [String: String] {
+        var fields: [String: String] = [:]
+        for line in note.components(separatedBy: .newlines) {
+            guard let colon = line.firstIndex(of: ":") else { continue }
+            let key = line[..<colon].trimmingCharacters(in: .whitespaces).lowercased()
+            let rawValue = line[line.index(after: colon)...]
+            guard !key.isEmpty, !rawValue.hasPrefix("//"),
+                  key.allSatisfy({ $0.isLetter || $0.isNumber || " -_".contains($0) })
+            else {
+                continue
+            }
+            let value = rawValue.trimmingCharacters(in: .whitespaces)
+            if fields[key] == nil {
+                fields[key] = value
+            }
+        }
+        return fields
+    }
+}
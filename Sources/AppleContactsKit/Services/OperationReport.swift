@@ -0,0 +1,68 @@
+import Foundation
+
+/// Per-record outcome of a command that changes many contacts (`--report`), so failures
+/// can be reviewed and re-attempted later with `--retry-failed`. Used by import, prune
+/// and fix-encoding, which keep going past a failed record when writing a report.
+struct OperationReport: Codable {
+    struct Record: Codable {
+        /// Position of the record in the command's input, e.g. the card's number in an
+        /// imported file (0 for the first). Retries use it to find records without an ID.
+        let index: Int
+        /// Contact ID, when the record is an existing contact
+        let id: String?
+        let name: String
+        /// What was attempted: added, updated, duplicated, skipped, deleted or fixed
+        let action: String
+        let success: Bool
+        let error: String?
+    }
+
+    /// Command that wrote the report, e.g. "import"
+    let command: String
+    /// The command's input, e.g. the imported file's path
+    let input: String?
+    let createdAt: Date
+    private(set) var records: [Record] = []
+
+    init(command: String, input: String? = nil, createdAt: Date = Date()) {
+        self.command = command
+        self.input = input
+        self.createdAt = createdAt
+    }
+
+    var failed: [Record] {
+        records.filter { !$0.success }
+    }
+
+    mutating func recordSuccess(index: Int, id: String? = nil, name: String, action: String) {
+        records.append(Record(index: index, id: id, name: name, action: action, success: true, error: nil))
+    }
+
+    mutating func recordFailure(index: Int, id: String? = nil, name: String, action: String, error: Error) {
+        let message = (error as? ContactsError)?.description ?? error.localizedDescription
+        records.append(Record(index: index, id: id, name: name, action: action, success: false, error: message))
+    }
+
+    /// Read a report written by `command`
+    static func load(_ path: String, command: String) throws -> OperationReport {
+        let decoder = JSONDecoder()
+        decoder.dateDecodingStrategy = .iso8601
+        guard let data = FileManager.default.contents(atPath: path),
+              let report = try? decoder.decode(OperationReport.self, from: data)
+        else {
+            throw ContactsError.invalidReport(path)
+        }
+        guard report.command == command else {
+            throw ContactsError.reportCommandMismatch(path, command: report.command)
+        }
+        return report
+    }
+
+    /// Write the report, replacing the file if it exists
+    func save(to path: String) throws {
+        let encoder = JSONEncoder()
+        encoder.dateEncodingStrategy = .iso8601
+        encoder.outputFormatting = [.prettyPrinted, .sortedKeys]
+        try encoder.encode(self).write(to: URL(fileURLWithPath: path), options: .atomic)
+    }
+}
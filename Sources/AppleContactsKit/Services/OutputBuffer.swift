@@ -0,0 +1,60 @@
+import Foundation
+
+/// Holds back everything written to stdout until the process exits (`--buffer-output`),
+/// so stdout arrives in one piece after all stderr diagnostics instead of interleaved
+/// with them. Output is collected in a temporary file rather than memory, so large
+/// listings don't need to fit in RAM.
+enum OutputBuffer {
+    nonisolated(unsafe) private static var savedStdout: Int32 = -1
+    nonisolated(unsafe) private static var file: UnsafeMutablePointer<FILE>?
+
+    /// Redirect stdout into the buffer until exit. Calling it again has no effect.
+    static func start() {
+        guard savedStdout < 0, let buffer = tmpfile() else {
+            return
+        }
+        fflush(stdout)
+        let saved = dup(STDOUT_FILENO)
+        guard saved >= 0, dup2(fileno(buffer), STDOUT_FILENO) >= 0 else {
+            fclose(buffer)
+            return
+        }
+        savedStdout = saved
+        file = buffer
+        // Runs on normal exit and on errors, which ArgumentParser reports before exiting
+        atexit { OutputBuffer.flush() }
+    }
+
+    /// Restore stdout and write everything buffered so far to it
+    static func flush() {
+        guard savedStdout >= 0, let file else {
+            return
+        }
+        fflush(stdout)
+        dup2(savedStdout, STDOUT_FILENO)
+        close(savedStdout)
+        savedStdout = -1
+        self.file = nil
+
+        rewind(file)
+        var chunk = [UInt8](repeating: 0, count: 64 * 1024)
+        while true {
+            let count = fread(&chunk, 1, chunk.count, file)
+            if count == 0 {
+                break
+            }
+            var written = 0
+            while written < count {
+                let result = chunk[written..<count].withUnsafeBytes { write(STDOUT_FILENO, $0.baseAddress, $0.count) }
+                if result <= 0 {
+                    break
+                }
+                written += result
+            }
+            if written < count {
+                break
+            }
+        }
+        fclose(file)
+    }
+}
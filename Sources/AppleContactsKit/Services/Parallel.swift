@@ -0,0 +1,47 @@
+import Foundation
+
+/// Bounded parallel work for slow per-item fetches, such as reading each group's members
+enum Parallel {
+    /// Transform every item using at most `concurrency` threads at a time, keeping the
+    /// results in item order. If any transform throws, the first error (by item order)
+    /// is rethrown after all work has finished.
+    static func map<T, R>(_ items: [T], concurrency: Int, _ transform: (T) throws -> R) throws -> [R] {
+        guard concurrency > 1, items.count > 1 else {
+            return try items.map(transform)
+        }
+
+        let state = State<R>(count: items.count)
+        DispatchQueue.concurrentPerform(iterations: min(concurrency, items.count)) { _ in
+            // Each worker takes the next unclaimed item until none are left
+            while let index = state.claim() {
+                state.store(Result { try transform(items[index]) }, at: index)
+            }
+        }
+        return try state.results.map { try $0!.get() }
+    }
+
+    /// Work queue and result slots shared by the workers
+    private final class State<R>: @unchecked Sendable {
+        private let lock = NSLock()
+        private var next = 0
+        private(set) var results: [Result<R, Error>?]
+
+        init(count: Int) {
+            results = Array(repeating: nil, count: count)
+        }
+
+        func claim() -> Int? {
+            lock.lock()
+            defer { lock.unlock() }
+            guard next < results.count else { return nil }
+            next += 1
+            return next - 1
+        }
+
+        func store(_ result: Result<R, Error>, at index: Int) {
+            lock.lock()
+            defer { lock.unlock() }
+            results[index] = result
+        }
+    }
+}
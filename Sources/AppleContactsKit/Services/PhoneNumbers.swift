@@ -0,0 +1,68 @@
+import Foundation
+
+/// Phone number normalization to an E.164-like form (`+<country code><number>`)
+enum PhoneNumbers {
+    /// International calling codes for regions we know how to normalize
+    static let callingCodes: [String: String] = [
+        "AT": "43", "AU": "61", "BE": "32", "BR": "55", "CA": "1", "CH": "41",
+        "CN": "86", "DE": "49", "DK": "45", "ES": "34", "FI": "358", "FR": "33",
+        "GB": "44", "IE": "353", "IN": "91", "IS": "354", "IT": "39", "JP": "81",
+        "MX": "52", "NL": "31", "NO": "47", "NZ": "64", "PL": "48", "PT": "351",
+        "SE": "46", "US": "1",
+    ]
+
+    /// Regions where a leading 0 is part of the number rather than a trunk prefix
+    static let keepsLeadingZero: Set<String> = ["IT"]
+
+    /// Region from the `APPLE_CONTACTS_PHONE_REGION` environment variable
+    static var environmentRegion: String? {
+        guard let env = ProcessInfo.processInfo.environment["APPLE_CONTACTS_PHONE_REGION"], !env.isEmpty else {
+            return nil
+        }
+        return env.uppercased()
+    }
+
+    /// Region from the system Language & Region settings
+    static var systemRegion: String? {
+        Locale.current.region?.identifier
+    }
+
+    /// ASCII digits and a leading + only. Other numeric characters (fractions,
+    /// superscripts, non-Latin digits) are dropped rather than kept as digits.
+    static func digits(_ number: String) -> String {
+        number.filter { isDigit($0) || $0 == "+" }
+    }
+
+    private static func isDigit(_ character: Character) -> Bool {
+        character.isASCII && character.isWholeNumber
+    }
+
+    /// Normalize a number, using the region to expand national numbers.
+    /// Numbers that can't be expanded are returned as bare digits.
+    static func normalize(_ number: String, region: String?) -> String {
+        let raw = digits(number)
+        let bare = raw.filter(isDigit)
+
+        if raw.hasPrefix("+") {
+            return "+" + bare
+        }
+        if bare.hasPrefix("00") {
+            return "+" + bare.dropFirst(2)
+        }
+
+        guard let region = region?.uppercased(), let code = callingCodes[region], !bare.isEmpty else {
+            return bare
+        }
+
+        // North American numbers written with the leading 1
+        if code == "1" && bare.count == 11 && bare.hasPrefix("1") {
+            return "+" + bare
+        }
+
+        var national = Substring(bare)
+        if national.hasPrefix("0") && !keepsLeadingZero.contains(region) {
+            national = national.dropFirst()
+        }
+        return "+" + code + national
+    }
+}
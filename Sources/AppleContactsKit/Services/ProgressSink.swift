@@ -0,0 +1,75 @@
+import ArgumentParser
+import Foundation
+
+/// Receives progress of long-running operations, e.g. so a GUI wrapper can show a progress bar
+protocol ProgressSink {
+    func report(done: Int, total: Int)
+}
+
+/// Writes progress as JSON lines (`{"done":120,"total":4000}`) to a file or an
+/// inherited file descriptor (`--progress-to`), keeping stdout free for the real output
+final class JSONProgressSink: ProgressSink {
+    enum Target: Equatable {
+        case descriptor(Int32)
+        case file(String)
+    }
+
+    private let handle: FileHandle
+    /// Report every this many items, plus the first and the last
+    private let every: Int
+
+    /// `fd3` or `fd:3` for an open file descriptor, anything else is a file path
+    static func parse(_ target: String) throws -> Target {
+        let lowered = target.lowercased()
+        guard lowered.hasPrefix("fd") else {
+            return .file(target)
+        }
+        let number = lowered.dropFirst(2).drop { $0 == ":" }
+        guard let descriptor = Int32(number), descriptor > 2 else {
+            throw ValidationError("Invalid --progress-to '\(target)'. Use a file path or fd3 and up (0-2 are stdin, stdout and stderr)")
+        }
+        return .descriptor(descriptor)
+    }
+
+    init(target: String, every: Int = 50) throws {
+        switch try Self.parse(target) {
+        case .descriptor(let descriptor):
+            guard fcntl(descriptor, F_GETFD) != -1 else {
+                throw ValidationError("--progress-to: file descriptor \(descriptor) is not open (e.g. run with 3>progress.jsonl)")
+            }
+            handle = FileHandle(fileDescriptor: descriptor, closeOnDealloc: false)
+        case .file(let path):
+            guard FileManager.default.createFile(atPath: path, contents: nil),
+                  let file = FileHandle(forWritingAtPath: path)
+            else {
+                throw ValidationError("--progress-to: cannot write to \(path)")
+            }
+            handle = file
+        }
+        self.every = max(1, every)
+    }
+
+    func report(done: Int, total: Int) {
+        guard done == 1 || done == total || done % every == 0 else {
+            return
+        }
+        handle.write(Data("{\"done\":\(done),\"total\":\(total)}\n".utf8))
+    }
+}
+
+/// Writes "Progress: 500/4000 contacts" lines to stderr every `every` items and at the
+/// end (`--progress-every`), for people watching a long export in a terminal
+final class TextProgressSink: ProgressSink {
+    private let every: Int
+
+    init(every: Int) {
+        self.every = max(1, every)
+    }
+
+    func report(done: Int, total: Int) {
+        guard done > 0, done == total || done % every == 0 else {
+            return
+        }
+        FileHandle.standardError.write(Data("Progress: \(done)/\(total) contacts\n".utf8))
+    }
+}
@@ -0,0 +1,77 @@
+import CoreImage
+import Foundation
+
+/// QR code generation and terminal rendering
+struct QRCode {
+    /// Module grid, true for dark modules, row by row from the top
+    let modules: [[Bool]]
+
+    /// Generate a QR code for the data, or nil if it doesn't fit
+    init?(data: Data) {
+        guard let filter = CIFilter(name: "CIQRCodeGenerator") else { return nil }
+        filter.setValue(data, forKey: "inputMessage")
+        filter.setValue("L", forKey: "inputCorrectionLevel")
+
+        // The generator renders one pixel per module
+        guard let image = filter.outputImage,
+              let cgImage = CIContext().createCGImage(image, from: image.extent)
+        else {
+            return nil
+        }
+
+        let width = cgImage.width
+        let height = cgImage.height
+        var pixels = [UInt8](repeating: 255, count: width * height)
+        let drawn = pixels.withUnsafeMutableBytes { buffer -> Bool in
+            guard let context = CGContext(
+                data: buffer.baseAddress,
+                width: width,
+                height: height,
+                bitsPerComponent: 8,
+                bytesPerRow: width,
+                space: CGColorSpaceCreateDeviceGray(),
+                bitmapInfo: CGImageAlphaInfo.none.rawValue
+            ) else {
+                return false
+            }
+            context.draw(cgImage, in: CGRect(x: 0, y: 0, width: width, height: height))
+            return true
+        }
+        guard drawn else { return nil }
+
+        modules = (0..<height).map { y in
+            (0..<width).map { x in pixels[y * width + x] < 128 }
+        }
+    }
+
+    /// Render using half-block characters, two module rows per line.
+    /// With color, dark modules are drawn black on a white background so the code scans
+    /// on any terminal theme; without color, light modules are drawn as blocks, which
+    /// suits the usual dark terminal background.
+    func render(color: Bool) -> String {
+        // Quiet zone of two modules around the code
+        let size = modules.first?.count ?? 0
+        let blank = [Bool](repeating: false, count: size + 4)
+        let padded = [blank, blank] + modules.map { [false, false] + $0 + [false, false] } + [blank, blank, blank]
+
+        var lines: [String] = []
+        for y in stride(from: 0, to: padded.count - 1, by: 2) {
+            let top = padded[y]
+            let bottom = padded[y + 1]
+            var line = ""
+            for x in 0..<top.count {
+                // Draw whichever colour the characters represent
+                let upper = color ? top[x] : !top[x]
+                let lower = color ? bottom[x] : !bottom[x]
+                switch (upper, lower) {
+                case (true, true): line += "█"
+                case (true, false): line += "▀"
+                case (false, true): line += "▄"
+                case (false, false): line += " "
+                }
+            }
+            lines.append(color ? "\u{001B}[30;47m\(line)\u{001B}[0m" : line)
+        }
+        return lines.joined(separator: "\n")
+    }
+}
@@ -0,0 +1,92 @@
+import ArgumentParser
+import Contacts
+import Foundation
+
+/// Masks sensitive values in displayed output (`--redact`), e.g. for screen sharing.
+/// Only used when printing; exports always contain the real values.
+struct Redaction {
+    enum Field: String, CaseIterable {
+        case phones
+        case emails
+        case addresses
+        case birthdays
+    }
+
+    static let mask = "•"
+
+    let fields: Set<Field>
+
+    /// No redaction
+    static let none = Redaction(fields: [])
+
+    init(fields: Set<Field>) {
+        self.fields = fields
+    }
+
+    /// Parse a comma-separated list of field types, or "all"
+    init(parsing spec: String) throws {
+        var fields = Set<Field>()
+        for raw in spec.split(separator: ",") {
+            let name = raw.trimmingCharacters(in: .whitespaces).lowercased()
+            if name == "all" {
+                fields = Set(Field.allCases)
+            } else if let field = Field(rawValue: name) ?? Field(rawValue: name + "s") {
+                fields.insert(field)
+            } else {
+                let available = (Field.allCases.map(\.rawValue) + ["all"]).joined(separator: ", ")
+                throw ValidationError("Unknown --redact field '\(name)'. Available: \(available)")
+            }
+        }
+        self.fields = fields
+    }
+
+    func phone(_ value: String) -> String {
+        fields.contains(.phones) ? Self.redactPhone(value) : value
+    }
+
+    func email(_ value: String) -> String {
+        fields.contains(.emails) ? Self.redactEmail(value) : value
+    }
+
+    /// A formatted address; only the city and country are kept
+    func address(_ value: CNPostalAddress, style: CNPostalAddressFormatterStyle = .mailingAddress) -> String {
+        guard fields.contains(.addresses) else {
+            return CNPostalAddressFormatter.string(from: value, style: style)
+        }
+        let visible = [value.city, value.country].filter { !$0.isEmpty }
+        return ([String(repeating: Self.mask, count: 3)] + visible).joined(separator: ", ")
+    }
+
+    func birthday(_ value: String) -> String {
+        guard fields.contains(.birthdays) else { return value }
+        return String(value.map { $0.isNumber ? Character(Self.mask) : $0 })
+    }
+
+    /// Mask every digit except an international prefix and the last two digits:
+    /// "+47 900 00 012" becomes "+47 ••• •• •12"
+    static func redactPhone(_ number: String) -> String {
+        let characters = Array(number)
+        let digitPositions = characters.indices.filter { characters[$0].isNumber }
+        var keep = Set(digitPositions.suffix(2))
+
+        // Keep the country code of numbers written with one
+        if characters.first == "+" {
+            for index in characters.indices.dropFirst() {
+                guard characters[index].isNumber else { break }
+                keep.insert(index)
+            }
+        }
+
+        return String(characters.indices.map { index in
+            characters[index].isNumber && !keep.contains(index) ? Character(mask) : characters[index]
+        })
+    }
+
+    /// Keep the first character of the local part and the domain: "erik@acme.com" becomes "e•••@acme.com"
+    static func redactEmail(_ email: String) -> String {
+        guard let at = email.lastIndex(of: "@"), at > email.startIndex else {
+            return String(repeating: mask, count: 3)
+        }
+        return "\(email[email.startIndex])\(String(repeating: mask, count: 3))\(email[at...])"
+    }
+}
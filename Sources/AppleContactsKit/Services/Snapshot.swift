@@ -0,0 +1,123 @@
+import Contacts
+import CryptoKit
+import Foundation
+
+/// Compact record of every contact's fields, for reporting later what changed.
+/// Each field is stored as a hash, so the file holds no contact data beyond names.
+/// Stored in `$XDG_CACHE_HOME/apple-contacts/snapshot.json` (default `~/.cache/apple-contacts/snapshot.json`)
+struct Snapshot: Codable {
+    struct Changes {
+        let added: [(id: String, name: String)]
+        let removed: [(id: String, name: String)]
+        let modified: [(id: String, name: String, fields: [String])]
+
+        var isEmpty: Bool {
+            added.isEmpty && removed.isEmpty && modified.isEmpty
+        }
+    }
+
+    let createdAt: Date
+    /// Contact ID to field name to hash of the field's value
+    let fields: [String: [String: String]]
+    /// Contact ID to display name, so removed contacts can be named
+    let names: [String: String]
+
+    /// Snapshot contacts fetched with `ContactsService.fullKeys`
+    init(contacts: [CNContact], createdAt: Date = Date()) {
+        self.createdAt = createdAt
+        var fields: [String: [String: String]] = [:]
+        var names: [String: String] = [:]
+        for contact in contacts {
+            fields[contact.identifier] = Self.fieldValues(of: contact).mapValues(Self.hash)
+            names[contact.identifier] = contact.displayName
+        }
+        self.fields = fields
+        self.names = names
+    }
+
+    /// Contacts added, removed and modified since `old`, each list sorted by name
+    func changes(since old: Snapshot) -> Changes {
+        func byName<T>(_ items: [T], _ name: (T) -> String) -> [T] {
+            items.sorted { name($0).localizedStandardCompare(name($1)) == .orderedAscending }
+        }
+
+        let added = fields.keys.filter { old.fields[$0] == nil }.map { (id: $0, name: names[$0] ?? "") }
+        let removed = old.fields.keys.filter { fields[$0] == nil }.map { (id: $0, name: old.names[$0] ?? "") }
+        let modified = fields.compactMap { id, current -> (id: String, name: String, fields: [String])? in
+            guard let previous = old.fields[id] else { return nil }
+            let changed = Set(previous.keys).union(current.keys).filter { previous[$0] != current[$0] }.sorted()
+            return changed.isEmpty ? nil : (id: id, name: names[id] ?? "", fields: changed)
+        }
+
+        return Changes(
+            added: byName(added) { $0.name },
+            removed: byName(removed) { $0.name },
+            modified: byName(modified) { $0.name }
+        )
+    }
+
+    /// Text of each non-empty field; multi-valued fields are sorted by label and value
+    /// so reordering alone isn't reported as a change
+    private static func fieldValues(of contact: CNContact) -> [String: String] {
+        var values: [String: String] = [:]
+        for field in CNContact.textFields {
+            values[field.name] = contact[keyPath: field.read]
+        }
+        values["birthday"] = contact.birthdayString
+
+        func labeled<T: NSCopying & NSSecureCoding>(_ items: [CNLabeledValue<T>], _ text: (T) -> String) -> String {
+            items.map { "\($0.label ?? ""):\(text($0.value))" }.sorted().joined(separator: "\n")
+        }
+        values["phones"] = labeled(contact.phoneNumbers) { $0.stringValue }
+        values["emails"] = labeled(contact.emailAddresses) { $0 as String }
+        values["addresses"] = labeled(contact.postalAddresses) {
+            CNPostalAddressFormatter.string(from: $0, style: .mailingAddress)
+        }
+        values["urls"] = labeled(contact.urlAddresses) { $0 as String }
+        values["socialProfiles"] = labeled(contact.socialProfiles) { "\($0.service):\($0.username)" }
+        values["relations"] = labeled(contact.contactRelations) { $0.name }
+
+        return values.filter { !$0.value.isEmpty }
+    }
+
+    /// Short SHA-256 of a value; enough to detect changes
+    private static func hash(_ value: String) -> String {
+        SHA256.hash(data: Data(value.utf8)).prefix(8).map { String(format: "%02x", $0) }.joined()
+    }
+
+    /// Location of the snapshot file
+    static var fileURL: URL {
+        let base: URL
+        if let xdg = ProcessInfo.processInfo.environment["XDG_CACHE_HOME"], !xdg.isEmpty {
+            base = URL(fileURLWithPath: xdg)
+        } else {
+            base = FileManager.default.homeDirectoryForCurrentUser.appendingPathComponent(".cache")
+        }
+        return base.appendingPathComponent("apple-contacts").appendingPathComponent("snapshot.json")
+    }
+
+    /// Load the saved snapshot
+    static func load() throws -> Snapshot {
+        let url = fileURL
+        guard FileManager.default.fileExists(atPath: url.path) else {
+            throw ContactsError.snapshotNotFound
+        }
+        let decoder = JSONDecoder()
+        decoder.dateDecodingStrategy = .iso8601
+        return try decoder.decode(Snapshot.self, from: Data(contentsOf: url))
+    }
+
+    /// Write the snapshot, replacing any earlier one
+    func save() throws {
+        let url = Self.fileURL
+        try FileManager.default.createDirectory(
+            at: url.deletingLastPathComponent(),
+            withIntermediateDirectories: true,
+            attributes: nil
+        )
+        let encoder = JSONEncoder()
+        encoder.dateEncodingStrategy = .iso8601
+        encoder.outputFormatting = .sortedKeys
+        try encoder.encode(self).write(to: url, options: .atomic)
+    }
+}
@@ -0,0 +1,26 @@
+import Foundation
+
+/// Terminal capability detection
+enum Terminal {
+    /// Whether stdout is an interactive terminal (not a pipe or file)
+    static var isInteractive: Bool {
+        isatty(STDOUT_FILENO) != 0
+    }
+
+    /// Whether stdin is an interactive terminal, so the user can answer a prompt
+    static var canPrompt: Bool {
+        isatty(STDIN_FILENO) != 0
+    }
+
+    /// Write a prompt to stderr, keeping stdout for results, and read the answer from stdin.
+    /// Returns nil at the end of input.
+    static func ask(_ prompt: String) -> String? {
+        FileHandle.standardError.write(Data(prompt.utf8))
+        return readLine()?.trimmingCharacters(in: .whitespaces)
+    }
+
+    /// Whether ANSI colors should be used: interactive, NO_COLOR unset, and not disabled by flag
+    static func colorEnabled(noColor: Bool) -> Bool {
+        isInteractive && !noColor && ProcessInfo.processInfo.environment["NO_COLOR"] == nil
+    }
+}
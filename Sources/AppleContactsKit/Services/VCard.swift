@@ -0,0 +1,208 @@
+import ArgumentParser
+import CryptoKit
+import Foundation
+
+/// Helpers for working with vCard text
+enum VCard {
+    /// Properties that identify a particular card rather than its content
+    static let identityProperties: Set<String> = ["UID", "REV", "PRODID", "X-ABUID"]
+
+    /// Split a vCard into unfolded content lines. Lines end in CRLF or LF only; other
+    /// Unicode line breaks (U+2028, U+0085, a lone CR) can appear inside values.
+    static func lines(_ vcard: String) -> [String] {
+        var result: [String] = []
+        for var line in vcard.components(separatedBy: "\n") {
+            if line.hasSuffix("\r") {
+                line.removeLast()
+            }
+            // Folded continuation lines start with a space or tab
+            if let first = line.first, first == " " || first == "\t", !result.isEmpty {
+                result[result.count - 1] += line.dropFirst()
+            } else if !line.isEmpty {
+                result.append(line)
+            }
+        }
+        return result
+    }
+
+    /// Property name of a content line, without group prefix or parameters (e.g. "TEL")
+    static func propertyName(_ line: String) -> String {
+        let name = line.prefix { $0 != ":" && $0 != ";" }
+        let withoutGroup = name.split(separator: ".").last ?? Substring(name)
+        return withoutGroup.uppercased()
+    }
+
+    /// Fields `export --exclude-fields` can remove, with the vCard properties that hold them
+    static let excludableFields: [String: [String]] = [
+        "note": ["NOTE"],
+        "birthday": ["BDAY"],
+        "photo": ["PHOTO"],
+        "address": ["ADR", "LABEL"],
+        "phone": ["TEL"],
+        "email": ["EMAIL"],
+        "url": ["URL"],
+        "nickname": ["NICKNAME"],
+        "org": ["ORG"],
+        "title": ["TITLE"],
+        "social": ["X-SOCIALPROFILE"],
+        "related": ["X-ABRELATEDNAMES"],
+        "im": ["IMPP"],
+        "dates": ["X-ABDATE"],
+    ]
+
+    /// Parse a comma-separated `--exclude-fields` list into property names. Besides the
+    /// names in `excludableFields`, any X- property can be given as-is (e.g. X-TWITTER).
+    static func parseExcludedFields(_ spec: String) throws -> Set<String> {
+        var properties = Set<String>()
+        for raw in spec.split(separator: ",") {
+            let name = raw.trimmingCharacters(in: .whitespaces)
+            if let mapped = excludableFields[name.lowercased()] {
+                properties.formUnion(mapped)
+            } else if name.uppercased().hasPrefix("X-") {
+                properties.insert(name.uppercased())
+            } else {
+                let available = excludableFields.keys.sorted().joined(separator: ", ")
+                throw ValidationError("Unknown --exclude-fields field '\(name)'. Available: \(available), or an X- property")
+            }
+        }
+        return properties
+    }
+
+    /// Remove every property with one of these names (e.g. NOTE, BDAY), including its folded
+    /// continuation lines. Grouped properties such as `item1.ADR` take the rest of their group
+    /// with them (`item1.X-ABLabel`), so no orphaned labels are left behind.
+    static func stripProperties(_ vcard: String, _ names: Set<String>) -> String {
+        let upperNames = Set(names.map { $0.uppercased() })
+        func group(_ line: String) -> String? {
+            let name = line.prefix { $0 != ":" && $0 != ";" }
+            guard let dot = name.firstIndex(of: ".") else { return nil }
+            return name[..<dot].uppercased()
+        }
+
+        let removedGroups = Set(lines(vcard).filter { upperNames.contains(propertyName($0)) }.compactMap(group))
+        let newline = vcard.contains("\r\n") ? "\r\n" : "\n"
+        var kept: [String] = []
+        var dropping = false
+        for line in vcard.components(separatedBy: newline) {
+            if let first = line.first, first == " " || first == "\t" {
+                // A continuation belongs to whatever its property line was
+                if !dropping {
+                    kept.append(line)
+                }
+                continue
+            }
+            dropping = !line.isEmpty && (upperNames.contains(propertyName(line)) || group(line).map(removedGroups.contains) == true)
+            if !dropping {
+                kept.append(line)
+            }
+        }
+        return kept.joined(separator: newline)
+    }
+
+    /// Whether the card has a property with this name (e.g. "PHOTO")
+    static func hasProperty(_ vcard: String, named name: String) -> Bool {
+        lines(vcard).contains { propertyName($0) == name.uppercased() }
+    }
+
+    /// Image type for a PHOTO property, detected from the data's magic bytes
+    static func imageType(of data: Data) -> String {
+        if data.starts(with: [0x89, 0x50, 0x4E, 0x47]) {
+            return "PNG"
+        }
+        if data.starts(with: [0x47, 0x49, 0x46]) {
+            return "GIF"
+        }
+        return "JPEG"
+    }
+
+    /// Add a base64 PHOTO property just before END:VCARD, folded at 75 octets.
+    /// `type` is the vCard image type, e.g. "JPEG".
+    static func injectPhoto(_ vcard: String, data: Data, type: String) -> String {
+        insertProperty("PHOTO;ENCODING=b;TYPE=\(type.uppercased()):\(data.base64EncodedString())", into: vcard)
+    }
+
+    /// Add `category` to the card's CATEGORIES, as its own CATEGORIES property just before
+    /// END:VCARD. Cards that already list the category (case-insensitive) are returned as-is.
+    static func addCategory(_ vcard: String, _ category: String) -> String {
+        let escaped = category
+            .replacingOccurrences(of: "\\", with: "\\\\")
+            .replacingOccurrences(of: ",", with: "\\,")
+            .replacingOccurrences(of: ";", with: "\\;")
+            .replacingOccurrences(of: "\n", with: "\\n")
+
+        // Values are comma-separated; escaped commas (\,) belong to the name
+        let existing = lines(vcard)
+            .filter { propertyName($0) == "CATEGORIES" }
+            .compactMap { line in line.firstIndex(of: ":").map { String(line[line.index(after: $0)...]) } }
+            .flatMap { value in
+                value.replacingOccurrences(of: "\\,", with: "\u{0}")
+                    .split(separator: ",")
+                    .map { $0.replacingOccurrences(of: "\u{0}", with: "\\,") }
+            }
+        if existing.contains(where: { $0.caseInsensitiveCompare(escaped) == .orderedSame }) {
+            return vcard
+        }
+        return insertProperty("CATEGORIES:\(escaped)", into: vcard)
+    }
+
+    /// Insert a content line just before END:VCARD, folded at 75 octets
+    private static func insertProperty(_ property: String, into vcard: String) -> String {
+        let newline = vcard.contains("\r\n") ? "\r\n" : "\n"
+        let line = foldLine(property, width: 75, newline: newline) + newline
+
+        guard let end = vcard.range(of: "END:VCARD", options: [.backwards, .caseInsensitive]) else {
+            return vcard + line
+        }
+        var result = vcard
+        result.insert(contentsOf: line, at: end.lowerBound)
+        return result
+    }
+
+    /// Fold a content line so no physical line is longer than `width` octets,
+    /// continuing with newline plus a space (RFC 6350 3.2). Never splits a UTF-8
+    /// sequence, so lines with multibyte characters may fold a little early.
+    /// A width of 0 leaves the line unfolded.
+    static func foldLine(_ line: String, width: Int, newline: String = "\r\n") -> String {
+        guard width > 0, line.utf8.count > width else {
+            return line
+        }
+
+        var parts: [String] = []
+        var current = String.UnicodeScalarView()
+        var currentBytes = 0
+        // Continuation lines lose one octet to the leading space
+        var limit = width
+        for scalar in line.unicodeScalars {
+            let size = UTF8.width(scalar)
+            if currentBytes + size > limit && currentBytes > 0 {
+                parts.append(String(current))
+                current = String.UnicodeScalarView()
+                currentBytes = 0
+                limit = max(1, width - 1)
+            }
+            current.append(scalar)
+            currentBytes += size
+        }
+        parts.append(String(current))
+        return parts.joined(separator: newline + " ")
+    }
+
+    /// Unfold a card and fold every line again at `width` octets (0 for no folding),
+    /// keeping the card's line endings
+    static func refold(_ vcard: String, width: Int) -> String {
+        let newline = vcard.contains("\r\n") ? "\r\n" : "\n"
+        return lines(vcard)
+            .map { foldLine($0, width: width, newline: newline) }
+            .joined(separator: newline) + newline
+    }
+
+    /// SHA-256 of a card's content, ignoring identity properties and line folding,
+    /// so identical cards from different sources hash the same
+    static func contentHash(_ vcard: String) -> String {
+        let content = lines(vcard)
+            .filter { !identityProperties.contains(propertyName($0)) }
+            .joined(separator: "\n")
+        let digest = SHA256.hash(data: Data(content.utf8))
+        return digest.map { String(format: "%02x", $0) }.joined()
+    }
+}
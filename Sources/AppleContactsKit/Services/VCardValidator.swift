@@ -0,0 +1,164 @@
+import Contacts
+import Foundation
+
+/// A structural problem found in a vCard file
+struct VCardIssue {
+    enum Severity: String {
+        case error
+        case warning
+    }
+
+    /// 1-based line where the problem starts, or 0 for the whole file
+    let line: Int
+    let severity: Severity
+    let message: String
+}
+
+/// Checks vCard files for problems that make importers reject or misread them
+enum VCardValidator {
+    static let knownVersions: Set<String> = ["2.1", "3.0", "4.0"]
+    static let knownEncodings: Set<String> = ["B", "BASE64", "QUOTED-PRINTABLE", "8BIT", "7BIT"]
+
+    /// Validate vCard text, returning issues in line order
+    static func validate(_ text: String) -> [VCardIssue] {
+        var issues: [VCardIssue] = []
+
+        // State of the card being read
+        var cardStart: Int?
+        var version: String?
+        var hasFN = false
+
+        func finishCard() {
+            guard let start = cardStart else { return }
+            if version == nil {
+                issues.append(VCardIssue(line: start, severity: .error, message: "Card has no VERSION"))
+            }
+            if !hasFN, version == "3.0" || version == "4.0" {
+                issues.append(VCardIssue(line: start, severity: .warning, message: "Card has no FN (required in vCard \(version ?? ""))"))
+            }
+            cardStart = nil
+        }
+
+        for (number, line) in logicalLines(text) {
+            guard let colon = line.firstIndex(of: ":") else {
+                issues.append(VCardIssue(line: number, severity: .error, message: "Line has no ':' separating name and value"))
+                continue
+            }
+
+            let name = VCard.propertyName(line)
+            let value = String(line[line.index(after: colon)...])
+            let parameters = line[..<colon].split(separator: ";").dropFirst().map { $0.uppercased() }
+
+            switch name {
+            case "BEGIN":
+                if cardStart != nil {
+                    issues.append(VCardIssue(line: cardStart ?? number, severity: .error, message: "Card is not terminated with END:VCARD"))
+                    finishCard()
+                }
+                cardStart = number
+                version = nil
+                hasFN = false
+                continue
+            case "END":
+                if cardStart == nil {
+                    issues.append(VCardIssue(line: number, severity: .error, message: "END:VCARD without a matching BEGIN:VCARD"))
+                }
+                finishCard()
+                continue
+            default:
+                break
+            }
+
+            if cardStart == nil {
+                issues.append(VCardIssue(line: number, severity: .error, message: "\(name) is outside a BEGIN:VCARD/END:VCARD block"))
+                continue
+            }
+
+            switch name {
+            case "VERSION":
+                let trimmed = value.trimmingCharacters(in: .whitespaces)
+                version = trimmed
+                if !knownVersions.contains(trimmed) {
+                    issues.append(VCardIssue(line: number, severity: .error, message: "Unsupported VERSION '\(trimmed)'"))
+                }
+            case "FN":
+                hasFN = !value.trimmingCharacters(in: .whitespaces).isEmpty
+            case "BDAY":
+                if !isValidDate(value) {
+                    issues.append(VCardIssue(line: number, severity: .error, message: "Invalid BDAY '\(value)'"))
+                }
+            default:
+                break
+            }
+
+            for parameter in parameters where parameter.hasPrefix("ENCODING=") {
+                let encoding = String(parameter.dropFirst("ENCODING=".count))
+                if !knownEncodings.contains(encoding) {
+                    issues.append(VCardIssue(line: number, severity: .error, message: "Unknown ENCODING '\(encoding)' on \(name)"))
+                }
+            }
+        }
+
+        if let start = cardStart {
+            issues.append(VCardIssue(line: start, severity: .error, message: "Card is not terminated with END:VCARD"))
+            finishCard()
+        }
+
+        // Finally, check that Contacts itself can read the file
+        if let data = text.data(using: .utf8), (try? CNContactVCardSerialization.contacts(with: data)) == nil {
+            issues.append(VCardIssue(line: 0, severity: .error, message: "Contacts could not parse the file"))
+        }
+
+        return issues.sorted { $0.line < $1.line }
+    }
+
+    /// Unfold continuation lines (leading space or tab, or quoted-printable soft breaks),
+    /// keeping the line number each logical line starts on
+    private static func logicalLines(_ text: String) -> [(number: Int, line: String)] {
+        var result: [(number: Int, line: String)] = []
+        var softBreak = false
+
+        for (index, raw) in text.components(separatedBy: "\n").enumerated() {
+            let line = raw.hasSuffix("\r") ? String(raw.dropLast()) : raw
+
+            if let first = line.first, first == " " || first == "\t", !result.isEmpty {
+                result[result.count - 1].line += line.dropFirst()
+            } else if softBreak, !result.isEmpty {
+                result[result.count - 1].line += line
+            } else if !line.isEmpty {
+                result.append((index + 1, line))
+            }
+
+            // Quoted-printable values continue after a trailing "="
+            if let last = result.last {
+                softBreak = last.line.uppercased().contains("QUOTED-PRINTABLE") && last.line.hasSuffix("=")
+                if softBreak {
+                    result[result.count - 1].line.removeLast()
+                }
+            }
+        }
+        return result
+    }
+
+    /// Dates as written by common vCard producers: 1990-01-25, 19900125, --01-25, --0125,
+    /// optionally followed by a time (T...)
+    private static func isValidDate(_ value: String) -> Bool {
+        let date = value.split(separator: "T", maxSplits: 1).first.map(String.init) ?? ""
+        let patterns = [
+            #"^\d{4}-\d{2}-\d{2}$"#,
+            #"^\d{8}$"#,
+            #"^--\d{2}-?\d{2}$"#,
+        ]
+        guard patterns.contains(where: { date.range(of: $0, options: .regularExpression) != nil }) else {
+            return false
+        }
+
+        // Check the month and day ranges
+        let digits = date.filter(\.isNumber)
+        let monthDay = digits.suffix(4)
+        guard let month = Int(monthDay.prefix(2)), let day = Int(monthDay.suffix(2)) else {
+            return false
+        }
+        return (1...12).contains(month) && (1...31).contains(day)
+    }
+}
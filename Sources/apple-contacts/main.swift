@@ -0,0 +1,3 @@
+import AppleContactsKit
+
+AppleContacts.main()
@@ -0,0 +1,47 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class ASCIITextTests: XCTestCase {
+    func testAccentsAreRemoved() {
+        XCTAssertEqual(ASCIIText.transliterate("José Müller"), "Jose Muller")
+        XCTAssertEqual(ASCIIText.transliterate("Françoise Čapek"), "Francoise Capek")
+    }
+
+    func testDecomposedAccentsAreRemoved() {
+        XCTAssertEqual(ASCIIText.transliterate("Jose\u{301}"), "Jose")
+    }
+
+    func testLettersWithoutABaseLetterAreSpelledOut() {
+        XCTAssertEqual(ASCIIText.transliterate("Åse Ødegård"), "Ase Odegard")
+        XCTAssertEqual(ASCIIText.transliterate("Straße"), "Strasse")
+        XCTAssertEqual(ASCIIText.transliterate("Æsop Łukasz"), "AEsop Lukasz")
+    }
+
+    func testOtherScriptsAreRomanized() {
+        XCTAssertEqual(ASCIIText.transliterate("Москва"), "Moskva")
+    }
+
+    func testUnmappableCharacters() {
+        XCTAssertEqual(ASCIIText.transliterate("Hi 👋", unmappable: .replace), "Hi ?")
+        XCTAssertEqual(ASCIIText.transliterate("Hi 👋", unmappable: .drop), "Hi ")
+    }
+
+    func testASCIIAndLineBreaksAreKept() {
+        let text = "BEGIN:VCARD\r\nFN:Erik Fisher\r\nEND:VCARD\r\n"
+        XCTAssertEqual(ASCIIText.transliterate(text), text)
+    }
+
+    func testAppendingEncodedTextLeavesTheExistingFileAlone() {
+        let earlier = CNMutableContact()
+        earlier.givenName = "Søren"
+        let existing = ExportFormat.csv.encode([(earlier, "")])
+        let added = CNMutableContact()
+        added.givenName = "Åse"
+        let encoded = ASCIIText.transliterate(ExportFormat.csv.encode([(added, "")]))
+
+        let combined = ExportFormat.csv.appending(encoded: encoded, to: existing)
+        XCTAssertTrue(combined?.hasPrefix(existing) == true)
+        XCTAssertTrue(combined?.contains(",Ase,") == true)
+    }
+}
@@ -0,0 +1,51 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class AddressFilterTests: XCTestCase {
+    /// Home in Oslo, work in Bergen, and a cabin under a custom label
+    private func contactWithMixedLabels() -> CNContact {
+        func address(city: String, postalCode: String) -> CNPostalAddress {
+            let address = CNMutablePostalAddress()
+            address.street = "Storgata 1"
+            address.city = city
+            address.postalCode = postalCode
+            address.country = "Norway"
+            return address
+        }
+        let contact = CNMutableContact()
+        contact.givenName = "Erik"
+        contact.postalAddresses = [
+            CNLabeledValue(label: CNLabelHome, value: address(city: "Oslo", postalCode: "0150")),
+            CNLabeledValue(label: CNLabelWork, value: address(city: "Bergen", postalCode: "5003")),
+            CNLabeledValue(label: "Cabin", value: address(city: "Geilo", postalCode: "3580")),
+        ]
+        return contact
+    }
+
+    func testLabelAndCityMustHoldForTheSameAddress() {
+        let contact = contactWithMixedLabels()
+        XCTAssertTrue(ContactsService.hasAddress(contact, label: "home", city: "oslo"))
+        XCTAssertTrue(ContactsService.hasAddress(contact, label: "work", city: "Bergen"))
+        XCTAssertFalse(ContactsService.hasAddress(contact, label: "work", city: "Oslo"))
+        XCTAssertFalse(ContactsService.hasAddress(contact, label: "home", postalCode: "5003"))
+    }
+
+    func testCustomLabelMatchesCaseInsensitively() {
+        let contact = contactWithMixedLabels()
+        XCTAssertTrue(ContactsService.hasAddress(contact, label: "cabin"))
+        XCTAssertTrue(ContactsService.hasAddress(contact, label: "Cabin", city: "Geilo"))
+        XCTAssertFalse(ContactsService.hasAddress(contact, label: "cabin", city: "Oslo"))
+    }
+
+    func testPostalCodeIgnoresWhitespace() {
+        let contact = contactWithMixedLabels()
+        XCTAssertTrue(ContactsService.hasAddress(contact, postalCode: "50 03"))
+        XCTAssertFalse(ContactsService.hasAddress(contact, postalCode: "9999"))
+    }
+
+    func testNoConditionsMatchesAnyAddress() {
+        XCTAssertTrue(ContactsService.hasAddress(contactWithMixedLabels()))
+        XCTAssertFalse(ContactsService.hasAddress(CNMutableContact()))
+    }
+}
@@ -0,0 +1,11 @@
+import XCTest
+@testable import AppleContactsKit
+
+/// The executable only runs `AppleContacts.main()`, so the root command and its
+/// subcommands have to be reachable through the library
+final class AppleContactsTests: XCTestCase {
+    func testSubcommandsParseThroughTheRootCommand() throws {
+        XCTAssertTrue(try AppleContacts.parseAsRoot(["list"]) is List)
+        XCTAssertTrue(try AppleContacts.parseAsRoot(["search", "Erik"]) is Search)
+    }
+}
@@ -0,0 +1,39 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class BirthdayParserTests: XCTestCase {
+    private func parse(_ input: String) throws -> [Int?] {
+        let components = try BirthdayParser.parse(input)
+        return [components.year, components.month, components.day]
+    }
+
+    func testNumericFormats() throws {
+        XCTAssertEqual(try parse("1985-03-14"), [1985, 3, 14])
+        XCTAssertEqual(try parse("19850314"), [1985, 3, 14])
+        XCTAssertEqual(try parse("03-14"), [nil, 3, 14])
+        XCTAssertEqual(try parse("--03-14"), [nil, 3, 14])
+    }
+
+    func testMonthNames() throws {
+        XCTAssertEqual(try parse("March 14"), [nil, 3, 14])
+        XCTAssertEqual(try parse("14 Mar 1985"), [1985, 3, 14])
+        XCTAssertEqual(try parse("Mar 14, 1985"), [1985, 3, 14])
+    }
+
+    func testPlaceholderYearMeansNoYear() throws {
+        XCTAssertEqual(try parse("1604-03-14"), [nil, 3, 14])
+    }
+
+    func testLeapDay() throws {
+        XCTAssertEqual(try parse("02-29"), [nil, 2, 29])
+        XCTAssertEqual(try parse("2024-02-29"), [2024, 2, 29])
+        XCTAssertThrowsError(try parse("2023-02-29"))
+    }
+
+    func testInvalidInput() {
+        XCTAssertThrowsError(try parse("13-01"))
+        XCTAssertThrowsError(try parse("04-31"))
+        XCTAssertThrowsError(try parse("Smarch 14"))
+        XCTAssertThrowsError(try parse("yesterday"))
+    }
+}
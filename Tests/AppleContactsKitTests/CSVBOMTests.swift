@@ -0,0 +1,41 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class CSVBOMTests: XCTestCase {
+    private let bom = ExportFormat.byteOrderMark
+    private let csv = ExportFormat.csv.encode([])
+
+    func testBOMOnlyWithFlag() {
+        XCTAssertEqual(Export.withBOM(csv, format: .csv, enabled: true), bom + csv)
+        XCTAssertEqual(Export.withBOM(csv, format: .csv, enabled: false), csv)
+    }
+
+    func testBOMOnlyForCSV() {
+        let json = ExportFormat.json.encode([])
+        XCTAssertEqual(Export.withBOM(json, format: .json, enabled: true), json)
+        let vcard = "BEGIN:VCARD\r\nFN:Erik Fisher\r\nEND:VCARD\r\n"
+        XCTAssertEqual(Export.withBOM(vcard, format: .vcard, enabled: true), vcard)
+    }
+
+    func testBOMIsNotDoubled() {
+        XCTAssertEqual(Export.withBOM(bom + csv, format: .csv, enabled: true), bom + csv)
+    }
+
+    func testAppendingToFileWithBOMKeepsSingleBOM() throws {
+        let existing = bom + csv + "Erik Fisher,Erik,Fisher,,,,1\n"
+        let combined = try XCTUnwrap(ExportFormat.csv.appending(encoded: csv, to: existing))
+        let written = Export.withBOM(combined, format: .csv, enabled: true)
+
+        XCTAssertTrue(written.hasPrefix(bom))
+        XCTAssertFalse(written.dropFirst().contains(bom))
+        XCTAssertEqual(written.components(separatedBy: "Name,First Name").count, 2)
+    }
+
+    func testAppendingToFileWithoutBOMAddsOneWithFlag() throws {
+        let existing = csv + "Erik Fisher,Erik,Fisher,,,,1\n"
+        let combined = try XCTUnwrap(ExportFormat.csv.appending(encoded: csv, to: existing))
+
+        XCTAssertEqual(Export.withBOM(combined, format: .csv, enabled: true), bom + combined)
+        XCTAssertEqual(Export.withBOM(combined, format: .csv, enabled: false), combined)
+    }
+}
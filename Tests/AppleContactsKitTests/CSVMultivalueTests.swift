@@ -0,0 +1,48 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class CSVMultivalueTests: XCTestCase {
+    private func contact(phones: [String], emails: [String] = []) -> CNContact {
+        let contact = CNMutableContact()
+        contact.givenName = "Erik"
+        contact.familyName = "Fisher"
+        contact.phoneNumbers = phones.map { CNLabeledValue(label: CNLabelPhoneNumberMobile, value: CNPhoneNumber(stringValue: $0)) }
+        contact.emailAddresses = emails.map { CNLabeledValue(label: CNLabelWork, value: $0 as NSString) }
+        return contact
+    }
+
+    private func rows(_ csv: String) -> [String] {
+        csv.components(separatedBy: "\n").filter { !$0.isEmpty }
+    }
+
+    func testThreePhonesGiveThreeRows() {
+        let exported: [ExportedContact] = [(contact(phones: ["+47 1", "+47 2", "+47 3"]), "")]
+        let lines = rows(ExportFormat.csv.encode(exported, multivalue: .expand))
+        XCTAssertEqual(lines.count, 4)
+        XCTAssertEqual(lines[0], "Name,First Name,Last Name,Organization,Field,Label Type,Value,ID")
+        XCTAssertTrue(lines[1...].allSatisfy { $0.contains(",phone,") })
+        XCTAssertEqual(lines[1...].map { $0.contains("+47 1") || $0.contains("+47 2") || $0.contains("+47 3") }, [true, true, true])
+    }
+
+    func testWideKeepsOneRowPerContact() {
+        let exported: [ExportedContact] = [(contact(phones: ["+47 1", "+47 2", "+47 3"], emails: ["erik@acme.com"]), "")]
+        let lines = rows(ExportFormat.csv.encode(exported, multivalue: .wide))
+        XCTAssertEqual(lines.count, 2)
+        XCTAssertTrue(lines[1].contains("+47 1; +47 2; +47 3"))
+    }
+
+    func testContactWithoutValuesStillGetsARow() {
+        let exported: [ExportedContact] = [(contact(phones: []), "")]
+        let lines = rows(ExportFormat.csv.encode(exported, multivalue: .expand))
+        XCTAssertEqual(lines.count, 2)
+        XCTAssertTrue(lines[1].hasPrefix("Erik Fisher,Erik,Fisher,"))
+    }
+
+    func testAppendingRejectsTheOtherLayout() {
+        let exported: [ExportedContact] = [(contact(phones: ["+47 1"]), "")]
+        let wide = ExportFormat.csv.encode(exported, multivalue: .wide)
+        XCTAssertNil(ExportFormat.csv.appending(exported, to: wide, multivalue: .expand))
+        XCTAssertNotNil(ExportFormat.csv.appending(exported, to: wide, multivalue: .wide))
+    }
+}
@@ -0,0 +1,28 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class CompactOptionTests: XCTestCase {
+    private let value: [[String: Any]] = [["id": "ABC:ABPerson", "name": "Erik Fisher", "phones": ["+47 900 00 000"]]]
+
+    private func json(_ arguments: [String]) throws -> String {
+        let option = try CompactOption.parse(arguments)
+        let data = try JSONSerialization.data(withJSONObject: value, options: option.writingOptions(.sortedKeys))
+        return String(decoding: data, as: UTF8.self)
+    }
+
+    func testCompactIsOneLine() throws {
+        XCTAssertEqual(try json(["--compact"]), #"[{"id":"ABC:ABPerson","name":"Erik Fisher","phones":["+47 900 00 000"]}]"#)
+    }
+
+    func testIndentedIsTheDefault() throws {
+        let pretty = try json([])
+        XCTAssertTrue(pretty.contains("\n"))
+        XCTAssertNotEqual(pretty, try json(["--compact"]))
+    }
+
+    func testBothParseToTheSameValue() throws {
+        let compact = try JSONSerialization.jsonObject(with: Data(try json(["--compact"]).utf8)) as? NSArray
+        let pretty = try JSONSerialization.jsonObject(with: Data(try json([]).utf8)) as? NSArray
+        XCTAssertEqual(compact, pretty)
+    }
+}
@@ -0,0 +1,50 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class DisplayNameTests: XCTestCase {
+    private func contact(given: String = "", family: String = "", organization: String = "", type: CNContactType = .person) -> CNContact {
+        let contact = CNMutableContact()
+        contact.givenName = given
+        contact.familyName = family
+        contact.organizationName = organization
+        contact.contactType = type
+        return contact
+    }
+
+    private func jsonName(_ contact: CNContact) throws -> String? {
+        let json = ExportFormat.json.encode([(contact, "")])
+        let entries = try XCTUnwrap(JSONSerialization.jsonObject(with: Data(json.utf8)) as? [[String: Any]])
+        return entries.first?["name"] as? String
+    }
+
+    private func csvName(_ contact: CNContact) -> String? {
+        let rows = ExportFormat.csv.encode([(contact, "")]).components(separatedBy: "\n")
+        return rows[1].components(separatedBy: ",").first
+    }
+
+    func testCompanyWithoutPersonNameUsesOrganization() throws {
+        let company = contact(organization: "Acme", type: .organization)
+        XCTAssertEqual(company.displayName, "Acme")
+        XCTAssertEqual(try jsonName(company), "Acme")
+        XCTAssertEqual(csvName(company), "Acme")
+    }
+
+    func testCompanyCardPrefersOrganizationOverPersonName() {
+        XCTAssertEqual(contact(given: "Erik", family: "Fisher", organization: "Acme", type: .organization).displayName, "Acme")
+    }
+
+    func testPersonWithoutNameFallsBackToOrganization() throws {
+        let person = contact(organization: "Acme")
+        XCTAssertEqual(person.displayName, "Acme")
+        XCTAssertEqual(csvName(person), "Acme")
+    }
+
+    func testPersonNameWins() {
+        XCTAssertEqual(contact(given: "Erik", family: "Fisher", organization: "Acme").displayName, "Erik Fisher")
+    }
+
+    func testEmptyCompanyCardHasEmptyName() {
+        XCTAssertEqual(contact(type: .organization).displayName, "")
+    }
+}
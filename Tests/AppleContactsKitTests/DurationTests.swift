@@ -0,0 +1,20 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class DurationTests: XCTestCase {
+    func testUnits() {
+        XCTAssertEqual(ModificationDates.interval(from: "30m"), 1800)
+        XCTAssertEqual(ModificationDates.interval(from: "12h"), 43200)
+        XCTAssertEqual(ModificationDates.interval(from: "7d"), 604_800)
+        XCTAssertEqual(ModificationDates.interval(from: "6mo"), 6 * 2_592_000)
+        XCTAssertEqual(ModificationDates.interval(from: "2Y"), 2 * 31_536_000)
+        XCTAssertNil(ModificationDates.interval(from: "7"))
+        XCTAssertNil(ModificationDates.interval(from: "-1d"))
+    }
+
+    func testLongUnitsRejectMinutesAndHours() {
+        XCTAssertNil(ModificationDates.interval(from: "6m", allowedUnits: ModificationDates.longUnits))
+        XCTAssertNil(ModificationDates.interval(from: "12h", allowedUnits: ModificationDates.longUnits))
+        XCTAssertEqual(ModificationDates.interval(from: "6mo", allowedUnits: ModificationDates.longUnits), 6 * 2_592_000)
+    }
+}
@@ -0,0 +1,45 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class EmailCaseTests: XCTestCase {
+    func testOnlyTheDomainIsLowercasedByDefault() {
+        XCTAssertEqual(EmailCase.normalizeEmail("Erik.Fisher@Example.COM"), "Erik.Fisher@example.com")
+        XCTAssertEqual(EmailCase.normalizeEmail("Erik.Fisher@Example.COM", wholeAddress: true), "erik.fisher@example.com")
+    }
+
+    func testValuesWithoutAtAreLeftAlone() {
+        XCTAssertEqual(EmailCase.normalizeEmail("Not An Address"), "Not An Address")
+        XCTAssertEqual(EmailCase.normalizeEmail("Not An Address", wholeAddress: true), "not an address")
+    }
+
+    func testDomain() {
+        XCTAssertEqual(EmailCase.domain(of: "erik@Example.com"), "example.com")
+        XCTAssertNil(EmailCase.domain(of: "erik"))
+    }
+
+    func testNormalizingKeepsLabels() {
+        let contact = CNMutableContact()
+        contact.emailAddresses = [CNLabeledValue(label: CNLabelWork, value: "Erik@ACME.com" as NSString)]
+        let normalized = EmailCase.normalizing(contact)
+        XCTAssertEqual(normalized.emailAddresses.first?.value as String?, "Erik@acme.com")
+        XCTAssertEqual(normalized.emailAddresses.first?.label, CNLabelWork)
+    }
+
+    func testExportNormalizeLowercasesDomainsInJSONAndCSV() throws {
+        let contact = CNMutableContact()
+        contact.givenName = "Erik"
+        contact.emailAddresses = [CNLabeledValue(label: CNLabelWork, value: "Erik.Fisher@Example.COM" as NSString)]
+
+        // export --normalize sets lowercaseEmails on the service
+        let service = ContactsService()
+        service.lowercaseEmails = true
+        let exported = [try service.exported(contact)]
+
+        for format in [ExportFormat.json, .csv, .vcard] {
+            let text = format.encode(exported)
+            XCTAssertTrue(text.contains("Erik.Fisher@example.com"), "\(format)")
+            XCTAssertFalse(text.contains("Example.COM"), "\(format)")
+        }
+    }
+}
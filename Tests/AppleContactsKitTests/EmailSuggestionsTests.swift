@@ -0,0 +1,42 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class EmailSuggestionsTests: XCTestCase {
+    func testCorrectAddressHasNoProblemOrFix() {
+        XCTAssertNil(EmailSuggestions.problem(with: "erik@gmail.com"))
+        XCTAssertNil(EmailSuggestions.suggestFix(for: "erik@gmail.com"))
+    }
+
+    func testMisspelledDomain() {
+        XCTAssertEqual(EmailSuggestions.problem(with: "erik@gmial.com"), "likely misspelled domain")
+        XCTAssertEqual(EmailSuggestions.suggestFix(for: "erik@gmial.com"), "erik@gmail.com")
+        XCTAssertEqual(EmailSuggestions.suggestFix(for: "erik@GMIAL.com"), "erik@gmail.com")
+    }
+
+    func testDoubleDots() {
+        XCTAssertEqual(EmailSuggestions.problem(with: "erik@example..com"), "misplaced dot in domain")
+        XCTAssertEqual(EmailSuggestions.suggestFix(for: "erik@example...com"), "erik@example.com")
+    }
+
+    func testTrailingDot() {
+        XCTAssertEqual(EmailSuggestions.problem(with: "erik@example.com."), "misplaced dot in domain")
+        XCTAssertEqual(EmailSuggestions.suggestFix(for: "erik@example.com."), "erik@example.com")
+    }
+
+    func testMissingAt() {
+        XCTAssertEqual(EmailSuggestions.problem(with: "erik.example.com"), "missing @")
+        XCTAssertNil(EmailSuggestions.suggestFix(for: "erik.example.com"))
+    }
+
+    func testWhitespaceIsTrimmed() {
+        XCTAssertEqual(EmailSuggestions.problem(with: " erik@example.com"), "leading or trailing whitespace")
+        XCTAssertEqual(EmailSuggestions.suggestFix(for: " erik@example.com "), "erik@example.com")
+    }
+
+    func testNoConfidentFix() {
+        XCTAssertEqual(EmailSuggestions.problem(with: "erik@localhost"), "domain has no top-level domain")
+        XCTAssertNil(EmailSuggestions.suggestFix(for: "erik@localhost"))
+        XCTAssertEqual(EmailSuggestions.problem(with: "a@b@c.com"), "more than one @")
+        XCTAssertNil(EmailSuggestions.suggestFix(for: "a@b@c.com"))
+    }
+}
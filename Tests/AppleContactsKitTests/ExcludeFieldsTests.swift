@@ -0,0 +1,40 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class ExcludeFieldsTests: XCTestCase {
+    private let card = [
+        "BEGIN:VCARD",
+        "VERSION:3.0",
+        "N:Fisher;Erik;;;",
+        "FN:Erik Fisher",
+        "NOTE:Met at the conference. This note is long enough that it has to be folded ",
+        " onto a second line",
+        "BDAY:1980-05-17",
+        "item1.ADR;type=HOME:;;Storgata 1;Oslo;;0150;Norway",
+        "item1.X-ABLabel:_$!<Home>!$_",
+        "TEL;type=CELL:+47 900 00 000",
+        "END:VCARD",
+        "",
+    ].joined(separator: "\r\n")
+
+    func testNoteAndBirthdayAreRemovedWithContinuationLines() throws {
+        let stripped = VCard.stripProperties(card, try VCard.parseExcludedFields("note,birthday"))
+        XCTAssertFalse(VCard.hasProperty(stripped, named: "NOTE"))
+        XCTAssertFalse(VCard.hasProperty(stripped, named: "BDAY"))
+        XCTAssertFalse(stripped.contains("onto a second line"))
+        XCTAssertTrue(VCard.hasProperty(stripped, named: "TEL"))
+        XCTAssertTrue(VCard.hasProperty(stripped, named: "ADR"))
+        XCTAssertTrue(stripped.hasSuffix("END:VCARD\r\n"))
+    }
+
+    func testGroupedPropertyTakesItsLabelAlong() throws {
+        let stripped = VCard.stripProperties(card, try VCard.parseExcludedFields("address"))
+        XCTAssertFalse(stripped.contains("item1."))
+        XCTAssertTrue(VCard.hasProperty(stripped, named: "NOTE"))
+    }
+
+    func testUnknownFieldIsRejected() {
+        XCTAssertThrowsError(try VCard.parseExcludedFields("note,shoe-size"))
+        XCTAssertEqual(try VCard.parseExcludedFields("x-twitter"), ["X-TWITTER"])
+    }
+}
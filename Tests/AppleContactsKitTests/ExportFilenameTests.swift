@@ -0,0 +1,69 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class ExportFilenameTests: XCTestCase {
+    private func filename(_ name: String) -> String {
+        var used = Set<String>()
+        return Export.uniqueFilename(for: name, used: &used)
+    }
+
+    func testEmojiSequencesAreKept() {
+        XCTAssertEqual(filename("Family 👨‍👩‍👧"), "Family 👨‍👩‍👧.vcf")
+        XCTAssertEqual(filename("🇳🇴 Friends"), "🇳🇴 Friends.vcf")
+    }
+
+    func testCombiningCharactersAreKept() {
+        let decomposed = "Jose\u{301}"
+        XCTAssertEqual(filename(decomposed), "\(decomposed).vcf")
+    }
+
+    func testRightToLeftNamesAndMarksAreKept() {
+        XCTAssertEqual(filename("עבודה"), "עבודה.vcf")
+        XCTAssertEqual(filename("Work \u{200F}عمل"), "Work \u{200F}عمل.vcf")
+        XCTAssertEqual(filename("a\u{200C}b\u{200E}"), "a\u{200C}b\u{200E}.vcf")
+    }
+
+    func testBidiOverridesAndIsolatesAreReplaced() {
+        // "Invoice\u{202E}fdp.vcf" would be listed as "Invoicefcv.pdf"
+        XCTAssertEqual(filename("Invoice\u{202E}fdp"), "Invoice_fdp.vcf")
+        XCTAssertEqual(filename("a\u{202A}b\u{2066}c\u{2069}"), "a_b_c_.vcf")
+    }
+
+    func testControlCharactersAreReplaced() {
+        XCTAssertEqual(filename("Tab\tName"), "Tab_Name.vcf")
+        XCTAssertEqual(filename("C1\u{85}Name\u{9B}"), "C1_Name_.vcf")
+        XCTAssertEqual(filename("Del\u{7F}"), "Del_.vcf")
+    }
+
+    func testUnsafeAndDuplicateNames() {
+        var used = Set<String>()
+        XCTAssertEqual(Export.uniqueFilename(for: "A/B", used: &used), "A_B.vcf")
+        XCTAssertEqual(Export.uniqueFilename(for: "a/b", used: &used), "a_b (2).vcf")
+        XCTAssertEqual(Export.uniqueFilename(for: ".hidden", used: &used), "group.hidden.vcf")
+        XCTAssertEqual(Export.uniqueFilename(for: "  ", used: &used), "group.vcf")
+    }
+
+    func testContactFilesUseTheirOwnFallback() {
+        var used = Set<String>()
+        XCTAssertEqual(Export.uniqueFilename(for: "", fallback: "contact", used: &used), "contact.vcf")
+        XCTAssertEqual(Export.uniqueFilename(for: "", fallback: "contact", used: &used), "contact (2).vcf")
+        XCTAssertEqual(Export.uniqueFilename(for: ".profile", fallback: "contact", used: &used), "contact.profile.vcf")
+        XCTAssertEqual(Export.uniqueFilename(for: "Erik Fisher", fallback: "contact", used: &used), "Erik Fisher.vcf")
+    }
+
+    func testCardFilenameSkipsOtherFilesButReusesAnUnjournaledCard() throws {
+        let directory = FileManager.default.temporaryDirectory
+            .appendingPathComponent(UUID().uuidString, isDirectory: true)
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+        defer { try? FileManager.default.removeItem(at: directory) }
+
+        let card = "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Jane Doe\r\nEND:VCARD\r\n"
+        try "someone else".write(to: directory.appendingPathComponent("Jane Doe.vcf"), atomically: true, encoding: .utf8)
+        try card.write(to: directory.appendingPathComponent("Jane Doe (2).vcf"), atomically: true, encoding: .utf8)
+
+        var used = Set<String>()
+        XCTAssertEqual(Export.cardFilename(for: "Jane Doe", card: card, in: directory, used: &used), "Jane Doe (2).vcf")
+        XCTAssertEqual(Export.cardFilename(for: "Jane Doe", card: card, in: directory, used: &used), "Jane Doe (3).vcf")
+        XCTAssertEqual(Export.cardFilename(for: "", card: card, in: directory, used: &used), "contact.vcf")
+    }
+}
@@ -0,0 +1,43 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class ExportNormalizationTests: XCTestCase {
+    private func contact(phones: [(String, String)], emails: [(String, String)]) -> CNContact {
+        let contact = CNMutableContact()
+        contact.givenName = "Erik"
+        contact.familyName = "Fisher"
+        contact.phoneNumbers = phones.map { CNLabeledValue(label: $0.0, value: CNPhoneNumber(stringValue: $0.1)) }
+        contact.emailAddresses = emails.map { CNLabeledValue(label: $0.0, value: $0.1 as NSString) }
+        return contact
+    }
+
+    private func json(_ contact: CNContact, service: ContactsService) throws -> String {
+        ExportFormat.json.encode([try service.exported(contact)])
+    }
+
+    func testStableOrderGivesTheSameJSONForShuffledValues() throws {
+        let phones = [(CNLabelWork, "+47 300"), (CNLabelPhoneNumberMobile, "+47 100"), (CNLabelHome, "+47 200")]
+        let emails = [(CNLabelWork, "erik@acme.com"), (CNLabelHome, "erik@home.no")]
+        let first = contact(phones: phones, emails: emails)
+        let shuffled = contact(phones: [phones[2], phones[0], phones[1]], emails: emails.reversed())
+
+        let service = ContactsService()
+        service.stableOrder = true
+        XCTAssertEqual(try json(first, service: service), try json(shuffled, service: service))
+
+        service.stableOrder = false
+        XCTAssertNotEqual(try json(first, service: service), try json(shuffled, service: service))
+    }
+
+    func testExportedContactMatchesItsVCard() throws {
+        let service = ContactsService()
+        service.stableOrder = true
+        let card = try service.exported(contact(
+            phones: [(CNLabelWork, "+47 300"), (CNLabelPhoneNumberMobile, "+47 100")],
+            emails: []
+        ))
+        let parsed = try XCTUnwrap(CNContactVCardSerialization.contacts(with: Data(card.vcard.utf8)).first)
+        XCTAssertEqual(parsed.phoneNumbers.map(\.value.stringValue), card.contact.phoneNumbers.map(\.value.stringValue))
+    }
+}
@@ -0,0 +1,96 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class FilterExpressionTests: XCTestCase {
+    private let erik: CNContact = {
+        let contact = CNMutableContact()
+        contact.givenName = "Erik"
+        contact.familyName = "Fisher"
+        contact.organizationName = "Acme"
+        contact.birthday = DateComponents(year: 1980, month: 5, day: 17)
+        contact.phoneNumbers = [
+            CNLabeledValue(label: CNLabelPhoneNumberMobile, value: CNPhoneNumber(stringValue: "+47 900 00 000")),
+            CNLabeledValue(label: "Cabin", value: CNPhoneNumber(stringValue: "+47 320 00 000")),
+        ]
+        contact.emailAddresses = [CNLabeledValue(label: CNLabelWork, value: "erik@acme.com" as NSString)]
+        return contact
+    }()
+
+    private func matches(_ expression: String, groups: [String] = []) throws -> Bool {
+        try FilterExpression(parsing: expression).matches(erik, groups: groups)
+    }
+
+    func testComparisonsAndLogic() throws {
+        XCTAssertTrue(try matches(#"org == "Acme" && len(phones) > 1"#))
+        XCTAssertTrue(try matches(#"org != "Acme" || lastName == "Fisher""#))
+        XCTAssertFalse(try matches(#"!(org == "Acme")"#))
+        XCTAssertTrue(try matches("age >= 18"))
+    }
+
+    func testPrecedenceBindsAndTighterThanOr() throws {
+        XCTAssertTrue(try matches(#"org == "Acme" || org == "Other" && lastName == "Nobody""#))
+        XCTAssertFalse(try matches(#"(org == "Acme" || org == "Other") && lastName == "Nobody""#))
+    }
+
+    func testTruthiness() throws {
+        XCTAssertTrue(try matches("emails"))
+        XCTAssertFalse(try matches("urls"))
+        XCTAssertFalse(try matches("nickname"))
+        XCTAssertFalse(try matches("nil"))
+    }
+
+    func testNilComparisonsAreFalse() throws {
+        XCTAssertFalse(try matches("nil > 3"))
+        XCTAssertFalse(try matches("nil < 3"))
+    }
+
+    func testInWithListsStringsAndGroups() throws {
+        XCTAssertTrue(try matches(#""erik@acme.com" in emails"#))
+        XCTAssertTrue(try matches(#""Fish" in lastName"#))
+        XCTAssertTrue(try matches(#""Family" in groups"#, groups: ["Family"]))
+        XCTAssertFalse(try matches(#""Family" in groups"#))
+    }
+
+    func testCleanLabelMatchesStoredLabelsNotLocalizedOnes() throws {
+        XCTAssertTrue(try matches(#""mobile" in cleanLabel(phoneLabels)"#))
+        XCTAssertTrue(try matches(#""cabin" in cleanLabel(phoneLabels)"#))
+        XCTAssertTrue(try matches(#"contains(cleanLabel(emailLabels), "work")"#))
+    }
+
+    func testStringHelpersIgnoreCaseAndApplyToLists() throws {
+        XCTAssertTrue(try matches(#"endsWith(emails, "@ACME.com")"#))
+        XCTAssertTrue(try matches(#"startsWith(phones, "+47 320")"#))
+        XCTAssertTrue(try matches(#"lower(org) == "acme""#))
+    }
+
+    func testMatchesUsesRegularExpressions() throws {
+        XCTAssertTrue(try matches(#"matches(phones, "^\\+47 9")"#))
+        XCTAssertFalse(try matches(#"matches(emails, "^bob@")"#))
+    }
+
+    func testInvalidRegexIsReportedWhileParsing() {
+        XCTAssertThrowsError(try FilterExpression(parsing: #"matches(emails, "([")"#)) { error in
+            XCTAssertTrue("\(error)".contains("invalid regular expression"))
+        }
+    }
+
+    func testParseErrors() {
+        XCTAssertThrowsError(try FilterExpression(parsing: "shoeSize > 40"))
+        XCTAssertThrowsError(try FilterExpression(parsing: "nope(org)"))
+        XCTAssertThrowsError(try FilterExpression(parsing: "len(org, phones)"))
+        XCTAssertThrowsError(try FilterExpression(parsing: #"org = "Acme""#))
+        XCTAssertThrowsError(try FilterExpression(parsing: #"org == "Acme"#))
+        XCTAssertThrowsError(try FilterExpression(parsing: "(org"))
+    }
+
+    func testTypeErrorsAreReportedWhenEvaluating() {
+        XCTAssertThrowsError(try matches("phones < 3"))
+        XCTAssertThrowsError(try matches("len(age)"))
+    }
+
+    func testUsesGroups() throws {
+        XCTAssertTrue(try FilterExpression(parsing: #"len(groups) == 0 || org == "Acme""#).usesGroups)
+        XCTAssertFalse(try FilterExpression(parsing: #"org == "Acme""#).usesGroups)
+    }
+}
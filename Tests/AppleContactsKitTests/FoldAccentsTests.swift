@@ -0,0 +1,31 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class FoldAccentsTests: XCTestCase {
+    func testDiacriticsAndCaseAreRemoved() {
+        XCTAssertEqual(ContactsService.foldAccents("José"), "jose")
+        XCTAssertEqual(ContactsService.foldAccents("ÅSE Ångström"), "ase angstrom")
+        XCTAssertEqual(ContactsService.foldAccents("Zoë Brontë"), "zoe bronte")
+    }
+
+    func testLettersWithoutDecomposition() {
+        XCTAssertEqual(ContactsService.foldAccents("Søren"), "soren")
+        XCTAssertEqual(ContactsService.foldAccents("ØRJAN"), "orjan")
+        XCTAssertEqual(ContactsService.foldAccents("Ærø"), "aero")
+        XCTAssertEqual(ContactsService.foldAccents("Œuvre"), "oeuvre")
+        XCTAssertEqual(ContactsService.foldAccents("Straße"), "strasse")
+        XCTAssertEqual(ContactsService.foldAccents("Łódź"), "lodz")
+        XCTAssertEqual(ContactsService.foldAccents("Đorđe"), "dorde")
+    }
+
+    func testPlainTextIsOnlyLowercased() {
+        XCTAssertEqual(ContactsService.foldAccents("Erik Fisher"), "erik fisher")
+        XCTAssertEqual(ContactsService.foldAccents(""), "")
+    }
+
+    func testFoldedQueryMatchesEitherSpelling() {
+        let name = ContactsService.foldAccents("Søren Kierkegård")
+        XCTAssertTrue(name.contains(ContactsService.foldAccents("soren")))
+        XCTAssertTrue(name.contains(ContactsService.foldAccents("KIERKEGÅRD")))
+    }
+}
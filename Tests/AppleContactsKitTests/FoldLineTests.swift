@@ -0,0 +1,66 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class FoldLineTests: XCTestCase {
+    /// Physical lines of a folded line, continuation lines still starting with a space
+    private func physicalLines(_ folded: String) -> [String] {
+        folded.components(separatedBy: "\r\n")
+    }
+
+    private func unfold(_ folded: String) -> String {
+        physicalLines(folded).enumerated().map { $0.offset == 0 ? $0.element : String($0.element.dropFirst()) }.joined()
+    }
+
+    func testShortLineIsUnchanged() {
+        XCTAssertEqual(VCard.foldLine("FN:Erik Fisher", width: 75), "FN:Erik Fisher")
+        XCTAssertEqual(VCard.foldLine(String(repeating: "x", count: 200), width: 0), String(repeating: "x", count: 200))
+    }
+
+    func testASCIIFoldsAtWidth() {
+        let line = "NOTE:" + String(repeating: "a", count: 200)
+        let lines = physicalLines(VCard.foldLine(line, width: 75))
+
+        XCTAssertEqual(lines[0].utf8.count, 75)
+        XCTAssertTrue(lines.dropFirst().allSatisfy { $0.hasPrefix(" ") && $0.utf8.count <= 75 })
+        XCTAssertEqual(lines[1].utf8.count, 75)
+        XCTAssertEqual(unfold(VCard.foldLine(line, width: 75)), line)
+    }
+
+    func testMultibyteScalarsAreNeverSplit() {
+        // 2-, 3- and 4-byte scalars, so fold points land mid-character if counted naively
+        let line = "NOTE:" + String(repeating: "ø日😀", count: 40)
+        let folded = VCard.foldLine(line, width: 75)
+
+        for physical in physicalLines(folded) {
+            XCTAssertLessThanOrEqual(physical.utf8.count, 75)
+        }
+        // Splitting the raw bytes at each fold must leave valid UTF-8 on both sides
+        let chunks = Array(folded.utf8).split(separator: UInt8(ascii: "\n")).map { Array($0) }
+        for chunk in chunks {
+            XCTAssertNotNil(String(bytes: chunk, encoding: .utf8))
+        }
+        XCTAssertEqual(unfold(folded), line)
+    }
+
+    func testContinuationLinesUseWidthMinusOne() {
+        let line = String(repeating: "é", count: 100)
+        let lines = physicalLines(VCard.foldLine(line, width: 11))
+
+        XCTAssertEqual(lines[0].utf8.count, 10)
+        for continuation in lines.dropFirst() {
+            XCTAssertTrue(continuation.hasPrefix(" "))
+            XCTAssertLessThanOrEqual(continuation.dropFirst().utf8.count, 10)
+        }
+    }
+
+    func testNewlineIsConfigurable() {
+        let folded = VCard.foldLine(String(repeating: "a", count: 10), width: 4, newline: "\n")
+        XCTAssertEqual(folded, "aaaa\n aaa\n aaa")
+    }
+
+    func testFoldedLinesUnfoldThroughLines() {
+        let note = "NOTE:" + String(repeating: "Søren 👨‍👩‍👧 ", count: 20)
+        let card = "BEGIN:VCARD\r\n" + VCard.foldLine(note, width: 75) + "\r\nEND:VCARD\r\n"
+        XCTAssertEqual(VCard.lines(card), ["BEGIN:VCARD", note, "END:VCARD"])
+    }
+}
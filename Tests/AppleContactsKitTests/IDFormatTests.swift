@@ -0,0 +1,28 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class IDFormatTests: XCTestCase {
+    func testUUIDDropsTheSuffix() {
+        XCTAssertEqual(IDFormat.uuid.apply("ABC-123:ABPerson"), "ABC-123")
+        XCTAssertEqual(IDFormat.uuid.apply("ABC-123"), "ABC-123")
+        XCTAssertEqual(IDFormat.full.apply("ABC-123:ABPerson"), "ABC-123:ABPerson")
+    }
+
+    func testExportFormatsUseTheIDFormat() throws {
+        let contact = CNMutableContact()
+        contact.givenName = "Erik"
+        let exported: [ExportedContact] = [(contact, "")]
+        let short = IDFormat.uuid.apply(contact.identifier)
+
+        let json = ExportFormat.json.encode(exported, idFormat: .uuid)
+        let entries = try XCTUnwrap(JSONSerialization.jsonObject(with: Data(json.utf8)) as? [[String: Any]])
+        XCTAssertEqual(entries.first?["id"] as? String, short)
+
+        for multivalue in CSVMultivalue.allCases {
+            let row = ExportFormat.csv.encode(exported, multivalue: multivalue, idFormat: .uuid)
+                .components(separatedBy: "\n")[1]
+            XCTAssertTrue(row.hasSuffix("," + short), row)
+        }
+    }
+}
@@ -0,0 +1,34 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class IDPrefixTests: XCTestCase {
+    private let ids = [
+        "1A2B3C4D-0000:ABPerson",
+        "1A2B3C4D-1111:ABPerson",
+        "9F8E7D6C-2222:ABPerson",
+    ]
+
+    func testExactID() throws {
+        XCTAssertEqual(try ContactsService.matchIDPrefix("9F8E7D6C-2222:ABPerson", in: ids), "9F8E7D6C-2222:ABPerson")
+    }
+
+    func testUniquePrefix() throws {
+        XCTAssertEqual(try ContactsService.matchIDPrefix("9F8E", in: ids), "9F8E7D6C-2222:ABPerson")
+        XCTAssertEqual(try ContactsService.matchIDPrefix("1A2B3C4D-1", in: ids), "1A2B3C4D-1111:ABPerson")
+    }
+
+    func testAmbiguousPrefix() {
+        XCTAssertThrowsError(try ContactsService.matchIDPrefix("1A2B", in: ids)) { error in
+            guard case ContactsError.ambiguousID(let prefix, let matches) = error else {
+                return XCTFail("unexpected error \(error)")
+            }
+            XCTAssertEqual(prefix, "1A2B")
+            XCTAssertEqual(matches, 2)
+        }
+    }
+
+    func testNoMatch() throws {
+        XCTAssertNil(try ContactsService.matchIDPrefix("FFFF", in: ids))
+        XCTAssertNil(try ContactsService.matchIDPrefix("", in: ids))
+    }
+}
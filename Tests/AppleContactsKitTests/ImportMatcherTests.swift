@@ -0,0 +1,56 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class ImportMatcherTests: XCTestCase {
+    private func card(_ given: String, _ family: String, email: String? = nil, phone: String? = nil) -> CNMutableContact {
+        let contact = CNMutableContact()
+        contact.givenName = given
+        contact.familyName = family
+        if let email {
+            contact.emailAddresses = [CNLabeledValue(label: CNLabelHome, value: email as NSString)]
+        }
+        if let phone {
+            contact.phoneNumbers = [CNLabeledValue(label: CNLabelPhoneNumberMobile, value: CNPhoneNumber(stringValue: phone))]
+        }
+        return contact
+    }
+
+    func testCardsAddedDuringTheImportAreMatched() {
+        var matcher = ImportMatcher(existing: [], region: "NO")
+        let first = card("Erik", "Fisher", email: "erik@acme.com")
+        XCTAssertNil(matcher.match(first))
+
+        matcher.insert(first)
+        XCTAssertEqual(matcher.match(card("Erik", "Fisher", email: "ERIK@acme.com"))?.contact.identifier, first.identifier)
+        XCTAssertEqual(matcher.sameName(card("erik", "fisher", phone: "+47 900 00 000"))?.identifier, first.identifier)
+    }
+
+    func testUpdatedContactReplacesTheSnapshot() {
+        let existing = card("Erik", "Fisher", email: "erik@acme.com")
+        var matcher = ImportMatcher(existing: [existing], region: "NO")
+
+        // The first update adds a phone number; a second card matching by that phone
+        // must find the merged version
+        let merged = existing.mutableCopy() as! CNMutableContact
+        merged.phoneNumbers = [CNLabeledValue(label: CNLabelPhoneNumberMobile, value: CNPhoneNumber(stringValue: "+47 900 00 000"))]
+        matcher.insert(merged)
+
+        let byPhone = matcher.match(card("E.", "Fisher", phone: "+4790000000"))
+        XCTAssertEqual(byPhone?.contact.identifier, existing.identifier)
+        XCTAssertEqual(byPhone?.contact.phoneNumbers.count, 1)
+        XCTAssertEqual(matcher.match(card("Erik", "Fisher", email: "erik@acme.com"))?.contact.phoneNumbers.count, 1)
+    }
+
+    func testRemovedValuesNoLongerMatch() {
+        let existing = card("Erik", "Fisher", email: "old@acme.com")
+        var matcher = ImportMatcher(existing: [existing], region: "NO")
+
+        let changed = existing.mutableCopy() as! CNMutableContact
+        changed.emailAddresses = [CNLabeledValue(label: CNLabelHome, value: "new@acme.com" as NSString)]
+        matcher.insert(changed)
+
+        XCTAssertNil(matcher.match(card("Someone", "Else", email: "old@acme.com")))
+        XCTAssertNotNil(matcher.match(card("Someone", "Else", email: "new@acme.com")))
+    }
+}
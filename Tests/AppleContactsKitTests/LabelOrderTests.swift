@@ -0,0 +1,35 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class LabelOrderTests: XCTestCase {
+    func testStandardLabelsAreLocalized() {
+        XCTAssertFalse(LabelOrder.isCustomLabel(CNLabelHome))
+        XCTAssertEqual(
+            LabelOrder.displayLabel(CNLabelHome),
+            CNLabeledValue<NSString>.localizedString(forLabel: CNLabelHome)
+        )
+    }
+
+    func testCustomLabelsKeepTheirCasing() {
+        XCTAssertTrue(LabelOrder.isCustomLabel("Summer House"))
+        XCTAssertEqual(LabelOrder.displayLabel("Summer House"), "Summer House")
+        XCTAssertEqual(LabelOrder.displayLabel("iCloud Mail"), "iCloud Mail")
+    }
+
+    func testMissingLabelReadsOther() {
+        let other = CNLabeledValue<NSString>.localizedString(forLabel: CNLabelOther)
+        XCTAssertEqual(LabelOrder.displayLabel(nil), other)
+        XCTAssertEqual(LabelOrder.displayLabel(""), other)
+    }
+
+    func testUnknownWrappedLabelIsUnwrapped() {
+        XCTAssertEqual(LabelOrder.displayLabel("_$!<Cabin>!$_"), "cabin")
+    }
+
+    func testCleanLabelIsLowercasedForComparison() {
+        XCTAssertEqual(LabelOrder.cleanLabel(CNLabelWork), "work")
+        XCTAssertEqual(LabelOrder.cleanLabel("Summer House"), "summer house")
+        XCTAssertEqual(LabelOrder.cleanLabel(nil), "")
+    }
+}
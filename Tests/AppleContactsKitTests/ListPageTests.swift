@@ -0,0 +1,29 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class ListPageTests: XCTestCase {
+    private let contacts: [CNContact] = (0..<5).map { _ in CNMutableContact() }
+
+    func testFirstPageIsSortedByID() {
+        let ids = List.page(contacts, after: "").map(\.identifier)
+        XCTAssertEqual(ids, contacts.map(\.identifier).sorted())
+    }
+
+    func testPageStartsAfterTheCursor() {
+        let sorted = List.page(contacts, after: "")
+        let next = List.page(contacts, after: sorted[1].identifier)
+        XCTAssertEqual(next.map(\.identifier), sorted[2...].map(\.identifier))
+        XCTAssertTrue(List.page(contacts, after: sorted[4].identifier).isEmpty)
+    }
+
+    func testCursorWithAndWithoutSuffixGiveTheSamePage() {
+        let cursor = List.page(contacts, after: "")[1].identifier
+        let short = IDFormat.shortenID(cursor)
+        XCTAssertEqual(
+            List.page(contacts, after: short + ":ABPerson").map(\.identifier),
+            List.page(contacts, after: short).map(\.identifier)
+        )
+        XCTAssertEqual(List.page(contacts, after: short).count, 3)
+    }
+}
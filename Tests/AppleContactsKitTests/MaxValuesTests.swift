@@ -0,0 +1,40 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class MaxValuesTests: XCTestCase {
+    private func contactWithPhones(_ count: Int) -> CNContact {
+        let contact = CNMutableContact()
+        contact.givenName = "Erik"
+        contact.phoneNumbers = (0..<count).map { index in
+            CNLabeledValue(label: CNLabelPhoneNumberMobile, value: CNPhoneNumber(stringValue: "+47 900 \(index)"))
+        }
+        return contact
+    }
+
+    func testLongSectionIsCapped() {
+        let phones = contactWithPhones(500).phoneNumbers
+        let section = Show.capped(phones, to: 10)
+
+        XCTAssertEqual(section.values.count, 10)
+        XCTAssertEqual(section.omitted, 490)
+        XCTAssertEqual(section.values.map(\.identifier), phones.prefix(10).map(\.identifier))
+    }
+
+    func testNoLimitKeepsEverything() {
+        let section = Show.capped(contactWithPhones(500).phoneNumbers, to: nil)
+        XCTAssertEqual(section.values.count, 500)
+        XCTAssertEqual(section.omitted, 0)
+    }
+
+    func testShortSectionIsUntouched() {
+        let section = Show.capped(contactWithPhones(3).phoneNumbers, to: 10)
+        XCTAssertEqual(section.values.count, 3)
+        XCTAssertEqual(section.omitted, 0)
+    }
+
+    func testOptionIsValidated() {
+        XCTAssertThrowsError(try Show.parse(["Erik", "--max-values", "0"]))
+        XCTAssertNoThrow(try Show.parse(["Erik", "--max-values", "10"]))
+    }
+}
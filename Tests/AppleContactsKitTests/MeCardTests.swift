@@ -0,0 +1,36 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class MeCardTests: XCTestCase {
+    func testDelimitersAreEscaped() {
+        XCTAssertEqual(MeCard.escape(#"a\b;c:d,e"#), #"a\\b\;c\:d\,e"#)
+    }
+
+    func testNamesWithDelimiters() {
+        let contact = CNMutableContact()
+        contact.givenName = "Erik; Jr"
+        contact.familyName = "Fisher, Sr"
+        XCTAssertEqual(MeCard.encode(contact), #"MECARD:N:Fisher\, Sr,Erik\; Jr;;"#)
+    }
+
+    func testCompanyCardUsesTheOrganization() {
+        let contact = CNMutableContact()
+        contact.contactType = .organization
+        contact.organizationName = "Acme: Oslo"
+        let card = MeCard.encode(contact)
+        XCTAssertEqual(card, #"MECARD:N:Acme\: Oslo;;"#)
+        XCTAssertFalse(card.contains("ORG:"))
+    }
+
+    func testBirthdayWithoutYearIsLeftOut() {
+        let contact = CNMutableContact()
+        contact.givenName = "Erik"
+        contact.familyName = "Fisher"
+        contact.birthday = DateComponents(month: 3, day: 14)
+        XCTAssertFalse(MeCard.encode(contact).contains("BDAY"))
+
+        contact.birthday = DateComponents(year: 1985, month: 3, day: 14)
+        XCTAssertTrue(MeCard.encode(contact).contains("BDAY:19850314;"))
+    }
+}
@@ -0,0 +1,59 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class MinimalVCardTests: XCTestCase {
+    private func contact() -> CNContact {
+        let contact = CNMutableContact()
+        contact.givenName = "Erik"
+        contact.familyName = "Fisher; Jr"
+        contact.organizationName = "Acme, Inc"
+        contact.jobTitle = "Engineer"
+        contact.phoneNumbers = [
+            CNLabeledValue(label: CNLabelPhoneNumberMobile, value: CNPhoneNumber(stringValue: "+47 900 00 000")),
+            CNLabeledValue(label: CNLabelWork, value: CNPhoneNumber(stringValue: "+47 22 00 00 00")),
+        ]
+        contact.emailAddresses = [CNLabeledValue(label: CNLabelWork, value: "erik@acme.com" as NSString)]
+        contact.birthday = DateComponents(month: 3, day: 14)
+        return contact
+    }
+
+    private func properties(_ card: String) -> [String] {
+        card.components(separatedBy: "\r\n")
+            .filter { !$0.isEmpty && !$0.hasPrefix("BEGIN") && !$0.hasPrefix("VERSION") && !$0.hasPrefix("END") }
+            .map { String($0.prefix { $0 != ":" && $0 != ";" }) }
+    }
+
+    func testOnlyRequestedPropertiesPlusNameAppear() throws {
+        let card = MinimalVCard.build(contact(), fields: try MinimalVCard.parse("phone"))
+        XCTAssertEqual(properties(card), ["N", "FN", "TEL"])
+        XCTAssertTrue(card.contains("TEL;TYPE=CELL:+47 900 00 000\r\n"))
+    }
+
+    func testNameIsAlwaysPresent() {
+        XCTAssertEqual(properties(MinimalVCard.build(contact(), fields: [])), ["N", "FN"])
+    }
+
+    func testValuesAreEscaped() throws {
+        let card = MinimalVCard.build(contact(), fields: try MinimalVCard.parse("name,org"))
+        XCTAssertTrue(card.contains("N:Fisher\\; Jr;Erik;;;\r\n"))
+        XCTAssertTrue(card.contains("ORG:Acme\\, Inc\r\n"))
+    }
+
+    func testUnknownFieldIsRejected() {
+        XCTAssertThrowsError(try MinimalVCard.parse("name,shoe-size"))
+    }
+
+    func testCardParsesBack() throws {
+        let card = MinimalVCard.build(contact(), fields: try MinimalVCard.parse("name,org,title,phones,email,birthday"))
+        let parsed = try XCTUnwrap(CNContactVCardSerialization.contacts(with: Data(card.utf8)).first)
+        XCTAssertEqual(parsed.givenName, "Erik")
+        XCTAssertEqual(parsed.familyName, "Fisher; Jr")
+        XCTAssertEqual(parsed.organizationName, "Acme, Inc")
+        XCTAssertEqual(parsed.jobTitle, "Engineer")
+        XCTAssertEqual(parsed.phoneNumbers.map(\.value.stringValue), ["+47 900 00 000", "+47 22 00 00 00"])
+        XCTAssertEqual(parsed.emailAddresses.map { $0.value as String }, ["erik@acme.com"])
+        XCTAssertEqual(parsed.birthday?.month, 3)
+        XCTAssertEqual(parsed.birthday?.day, 14)
+    }
+}
@@ -0,0 +1,44 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class NameLocaleTests: XCTestCase {
+    private func contact(given: String, family: String, organization: String = "") -> CNContact {
+        let contact = CNMutableContact()
+        contact.givenName = given
+        contact.familyName = family
+        contact.organizationName = organization
+        return contact
+    }
+
+    func testJapaneseLocalePutsTheFamilyNameFirst() {
+        XCTAssertTrue(contact(given: "太郎", family: "山田").fullName(locale: Locale(identifier: "ja-JP")).hasPrefix("山田"))
+    }
+
+    func testEnglishLocalePutsTheGivenNameFirst() {
+        XCTAssertEqual(contact(given: "Erik", family: "Fisher").fullName(locale: Locale(identifier: "en-US")), "Erik Fisher")
+    }
+
+    func testEmptyNameFallsBackToTheFormatter() {
+        XCTAssertEqual(CNMutableContact().fullName(locale: Locale(identifier: "en-US")), "")
+    }
+
+    func testDisplayNameUsesTheLocaleAndFallsBackToOrganization() {
+        let japanese = Locale(identifier: "ja-JP")
+        XCTAssertTrue(contact(given: "太郎", family: "山田").displayName(locale: japanese).hasPrefix("山田"))
+        XCTAssertEqual(contact(given: "", family: "", organization: "Acme").displayName(locale: japanese), "Acme")
+    }
+
+    func testServiceOnlyComposesNamesWithLocaleOption() {
+        let service = ContactsService()
+        service.locale = Locale(identifier: "ja-JP")
+        XCTAssertNil(service.nameLocale)
+        service.sortsByLocale = true
+        XCTAssertEqual(service.nameLocale?.identifier, "ja-JP")
+    }
+
+    func testGlobalOptionsPassTheLocaleExplicitly() throws {
+        XCTAssertNil(try GlobalOptions.parse([]).nameLocale)
+        XCTAssertEqual(try GlobalOptions.parse(["--locale", "ja-JP"]).nameLocale?.language.languageCode?.identifier, "ja")
+    }
+}
@@ -0,0 +1,22 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class NamePartTests: XCTestCase {
+    func testContainsIsCaseInsensitive() {
+        XCTAssertTrue(ContactsService.namePartMatches("Fisher", query: "fish", exact: false))
+        XCTAssertTrue(ContactsService.namePartMatches("Fishermann", query: "Fisher", exact: false))
+        XCTAssertFalse(ContactsService.namePartMatches("Erik", query: "Fisher", exact: false))
+    }
+
+    func testExactComparesTheWholeName() {
+        XCTAssertTrue(ContactsService.namePartMatches("Fisher", query: "fisher", exact: true))
+        XCTAssertTrue(ContactsService.namePartMatches(" Fisher ", query: "Fisher", exact: true))
+        XCTAssertFalse(ContactsService.namePartMatches("Fishermann", query: "Fisher", exact: true))
+    }
+
+    func testFoldingAccents() {
+        XCTAssertFalse(ContactsService.namePartMatches("José", query: "jose", exact: true))
+        XCTAssertTrue(ContactsService.namePartMatches("José", query: "jose", exact: true, foldAccents: true))
+        XCTAssertTrue(ContactsService.namePartMatches("Søren", query: "soren", exact: false, foldAccents: true))
+    }
+}
@@ -0,0 +1,42 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class OrganizationMatchTests: XCTestCase {
+    private func matches(_ organization: String, _ query: String, exact: Bool, foldAccents: Bool = false) -> Bool {
+        ContactsService.fieldMatches(organization, query: query, exact: exact, foldAccents: foldAccents)
+    }
+
+    func testContainsMatchesPartOfTheName() {
+        XCTAssertTrue(matches("Acme Holdings", "Acme", exact: false))
+        XCTAssertTrue(matches("Acme Holdings", "holdings", exact: false))
+        XCTAssertFalse(matches("Initech", "Acme", exact: false))
+    }
+
+    func testExactMatchesOnlyTheWholeName() {
+        XCTAssertTrue(matches("Acme", "Acme", exact: true))
+        XCTAssertFalse(matches("Acme Holdings", "Acme", exact: true))
+        XCTAssertFalse(matches("The Acme", "Acme", exact: true))
+    }
+
+    func testExactIgnoresCaseAndSurroundingWhitespace() {
+        XCTAssertTrue(matches("  ACME ", "acme", exact: true))
+        XCTAssertTrue(matches("Acme", " Acme  ", exact: true))
+    }
+
+    func testAccentsOnlyWithFolding() {
+        XCTAssertFalse(matches("Café Søren", "cafe soren", exact: true))
+        XCTAssertTrue(matches("Café Søren", "cafe soren", exact: true, foldAccents: true))
+        XCTAssertTrue(matches("Café Søren AS", "cafe", exact: false, foldAccents: true))
+    }
+
+    func testEmptyOrganizationOnlyMatchesEmptyExactQuery() {
+        XCTAssertFalse(matches("", "Acme", exact: true))
+        XCTAssertFalse(matches("", "Acme", exact: false))
+    }
+
+    func testSearchParsesOrgExact() throws {
+        let search = try Search.parse(["--org-exact", "Acme"])
+        XCTAssertEqual(search.orgExact, "Acme")
+        XCTAssertThrowsError(try Search.parse(["--org", "Acme", "--org-exact", "Acme"]))
+    }
+}
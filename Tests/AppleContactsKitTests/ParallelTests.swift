@@ -0,0 +1,68 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class ParallelTests: XCTestCase {
+    private struct Failure: Error, Equatable {
+        let index: Int
+    }
+
+    /// Tracks how many transforms run at once
+    private final class InFlight: @unchecked Sendable {
+        private let lock = NSLock()
+        private var current = 0
+        private(set) var peak = 0
+
+        func enter() {
+            lock.lock()
+            defer { lock.unlock() }
+            current += 1
+            peak = max(peak, current)
+        }
+
+        func leave() {
+            lock.lock()
+            defer { lock.unlock() }
+            current -= 1
+        }
+    }
+
+    func testWorkOverlaps() throws {
+        let inFlight = InFlight()
+        _ = try Parallel.map(Array(0..<16), concurrency: 4) { item -> Int in
+            inFlight.enter()
+            defer { inFlight.leave() }
+            Thread.sleep(forTimeInterval: 0.02)
+            return item
+        }
+        XCTAssertGreaterThan(inFlight.peak, 1)
+        XCTAssertLessThanOrEqual(inFlight.peak, 4)
+    }
+
+    func testOrderIsPreserved() throws {
+        let items = Array(0..<50)
+        let results = try Parallel.map(items, concurrency: 8) { item -> String in
+            // Earlier items finish last
+            Thread.sleep(forTimeInterval: Double(50 - item) / 10_000)
+            return "item \(item)"
+        }
+        XCTAssertEqual(results, items.map { "item \($0)" })
+    }
+
+    func testFirstErrorByIndexIsRethrown() {
+        XCTAssertThrowsError(try Parallel.map(Array(0..<20), concurrency: 4) { item -> Int in
+            if item == 7 || item == 15 {
+                // The later failure finishes first
+                Thread.sleep(forTimeInterval: item == 7 ? 0.05 : 0)
+                throw Failure(index: item)
+            }
+            return item
+        }) { error in
+            XCTAssertEqual(error as? Failure, Failure(index: 7))
+        }
+    }
+
+    func testSerialFallback() throws {
+        XCTAssertEqual(try Parallel.map([1, 2, 3], concurrency: 1) { $0 * 2 }, [2, 4, 6])
+        XCTAssertEqual(try Parallel.map([Int](), concurrency: 4) { $0 * 2 }, [])
+    }
+}
@@ -0,0 +1,40 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class PhoneNumbersTests: XCTestCase {
+    func testInternationalPlusPrefix() {
+        XCTAssertEqual(PhoneNumbers.normalize("+47 900 00 000", region: nil), "+4790000000")
+        XCTAssertEqual(PhoneNumbers.normalize("+47 900 00 000", region: "SE"), "+4790000000")
+    }
+
+    func testInternationalZeroZeroPrefix() {
+        XCTAssertEqual(PhoneNumbers.normalize("0047 900 00 000", region: nil), "+4790000000")
+        XCTAssertEqual(PhoneNumbers.normalize("0047-900-00-000", region: "NO"), "+4790000000")
+    }
+
+    func testNationalNumberUsesRegion() {
+        XCTAssertEqual(PhoneNumbers.normalize("(900) 00-000", region: "NO"), "+4790000000")
+        XCTAssertEqual(PhoneNumbers.normalize("(900) 00-000", region: "no"), "+4790000000")
+        XCTAssertEqual(PhoneNumbers.normalize("(900) 00-000", region: nil), "90000000")
+    }
+
+    func testTrunkPrefixIsDropped() {
+        XCTAssertEqual(PhoneNumbers.normalize("070-123 45 67", region: "SE"), "+46701234567")
+        XCTAssertEqual(PhoneNumbers.normalize("06 1234 5678", region: "IT"), "+390612345678")
+    }
+
+    func testNorthAmericanLeadingOne() {
+        XCTAssertEqual(PhoneNumbers.normalize("1 (415) 555-0100", region: "US"), "+14155550100")
+        XCTAssertEqual(PhoneNumbers.normalize("(415) 555-0100", region: "US"), "+14155550100")
+    }
+
+    func testUnknownRegionKeepsDigits() {
+        XCTAssertEqual(PhoneNumbers.normalize("900 00 000", region: "ZZ"), "90000000")
+    }
+
+    func testDigitsAreASCIIOnly() {
+        XCTAssertEqual(PhoneNumbers.digits("+47 (900) 00-000"), "+4790000000")
+        XCTAssertEqual(PhoneNumbers.digits("٩٠٠ ½ 12²"), "12")
+        XCTAssertEqual(PhoneNumbers.normalize("٩٠٠ 123", region: nil), "123")
+    }
+}
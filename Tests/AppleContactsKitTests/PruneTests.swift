@@ -0,0 +1,35 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class PruneTests: XCTestCase {
+    func testBlankContactIsEmpty() {
+        let contact = CNMutableContact()
+        contact.givenName = "  "
+        XCTAssertTrue(contact.isEffectivelyEmpty)
+    }
+
+    func testAnySingleFieldKeepsTheContact() {
+        let fields: [(String, (CNMutableContact) -> Void)] = [
+            ("job title", { $0.jobTitle = "Intern" }),
+            ("department", { $0.departmentName = "Sales" }),
+            ("phonetic name", { $0.phoneticFamilyName = "Fisha" }),
+            ("address", { $0.postalAddresses = [CNLabeledValue(label: CNLabelHome, value: CNPostalAddress())] }),
+            ("URL", { $0.urlAddresses = [CNLabeledValue(label: CNLabelWork, value: "https://example.com" as NSString)] }),
+            ("social profile", {
+                $0.socialProfiles = [CNLabeledValue(label: nil, value: CNSocialProfile(urlString: nil, username: "erik", userIdentifier: nil, service: CNSocialProfileServiceTwitter))]
+            }),
+            ("instant message", {
+                $0.instantMessageAddresses = [CNLabeledValue(label: nil, value: CNInstantMessageAddress(username: "erik", service: CNInstantMessageServiceSkype))]
+            }),
+            ("related name", { $0.contactRelations = [CNLabeledValue(label: CNLabelContactRelationSister, value: CNContactRelation(name: "Anna"))] }),
+            ("birthday", { $0.birthday = DateComponents(month: 5, day: 17) }),
+            ("date", { $0.dates = [CNLabeledValue(label: CNLabelDateAnniversary, value: DateComponents(month: 6, day: 1) as NSDateComponents)] }),
+        ]
+        for (field, fill) in fields {
+            let contact = CNMutableContact()
+            fill(contact)
+            XCTAssertFalse(contact.isEffectivelyEmpty, "a contact with only a \(field) was treated as empty")
+        }
+    }
+}
@@ -0,0 +1,35 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class SortedByNameTests: XCTestCase {
+    private func contacts(_ names: [String]) -> [CNContact] {
+        names.map { name in
+            let contact = CNMutableContact()
+            contact.givenName = name
+            contact.familyName = "Fisher"
+            return contact
+        }
+    }
+
+    private func sorted(_ names: [String], locale: String) -> [String] {
+        let service = ContactsService()
+        service.locale = Locale(identifier: locale)
+        return service.sortedByName(contacts(names)).map(\.givenName)
+    }
+
+    func testLocaleCollation() {
+        XCTAssertEqual(sorted(["Åse", "Zed", "Anna"], locale: "nb-NO"), ["Anna", "Zed", "Åse"])
+        XCTAssertEqual(sorted(["Åse", "Zed", "Anna"], locale: "en-US"), ["Anna", "Åse", "Zed"])
+    }
+
+    func testCaseInsensitiveAndNumeric() {
+        XCTAssertEqual(sorted(["erik", "Anna", "Bob"], locale: "en-US"), ["Anna", "Bob", "erik"])
+        XCTAssertEqual(sorted(["Room 10", "Room 9"], locale: "en-US"), ["Room 9", "Room 10"])
+    }
+
+    func testSameNamesStayTogether() {
+        let names = sorted(["Erik", "Anna", "erik", "Anna", "Erik"], locale: "en-US").map { $0.lowercased() }
+        XCTAssertEqual(names, ["anna", "anna", "erik", "erik", "erik"])
+    }
+}
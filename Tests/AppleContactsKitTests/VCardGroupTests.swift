@@ -0,0 +1,32 @@
+import Contacts
+import XCTest
+@testable import AppleContactsKit
+
+final class VCardGroupTests: XCTestCase {
+    private let card = "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Erik Fisher\r\nEND:VCARD\r\n"
+
+    func testCategoryIsAddedBeforeEnd() {
+        let tagged = VCard.addCategory(card, "Family")
+        XCTAssertEqual(tagged, "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Erik Fisher\r\nCATEGORIES:Family\r\nEND:VCARD\r\n")
+    }
+
+    func testExistingCategoryIsNotRepeated() {
+        let tagged = VCard.addCategory(VCard.addCategory(card, "Family"), "family")
+        XCTAssertEqual(tagged.components(separatedBy: "CATEGORIES").count, 2)
+    }
+
+    func testCommasAndSemicolonsAreEscaped() {
+        let tagged = VCard.addCategory(card, "Work, Oslo; 2024")
+        XCTAssertTrue(tagged.contains("CATEGORIES:Work\\, Oslo\\; 2024\r\n"))
+        // The escaped comma keeps it one category, so adding it again changes nothing
+        XCTAssertEqual(VCard.addCategory(tagged, "Work, Oslo; 2024"), tagged)
+    }
+
+    func testCategorySurvivesAMinimalCard() throws {
+        // export --group --vcard-group --only name,phone tags the rebuilt card
+        let contact = CNMutableContact()
+        contact.givenName = "Erik"
+        let minimal = MinimalVCard.build(contact, fields: try MinimalVCard.parse("name,phone"))
+        XCTAssertTrue(VCard.addCategory(minimal, "Family").contains("CATEGORIES:Family"))
+    }
+}
@@ -0,0 +1,26 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class VCardLinesTests: XCTestCase {
+    func testCRLFAndLFBothEndLines() {
+        let expected = ["BEGIN:VCARD", "FN:Erik Fisher", "END:VCARD"]
+        XCTAssertEqual(VCard.lines("BEGIN:VCARD\r\nFN:Erik Fisher\r\nEND:VCARD\r\n"), expected)
+        XCTAssertEqual(VCard.lines("BEGIN:VCARD\nFN:Erik Fisher\nEND:VCARD\n"), expected)
+    }
+
+    func testOtherLineBreaksStayInsideValues() {
+        let note = "NOTE:first\u{2028}second\u{85}third\u{2029}fourth\rfifth"
+        let lines = VCard.lines("BEGIN:VCARD\r\n\(note)\r\nEND:VCARD\r\n")
+        XCTAssertEqual(lines, ["BEGIN:VCARD", note, "END:VCARD"])
+    }
+
+    func testFoldedLinesAreJoined() {
+        let lines = VCard.lines("BEGIN:VCARD\r\nNOTE:a long\r\n  note\r\n\tcontinued\r\nEND:VCARD\r\n")
+        XCTAssertEqual(lines[1], "NOTE:a long notecontinued")
+    }
+
+    func testRefoldKeepsValueLineBreaks() {
+        let card = "BEGIN:VCARD\r\nNOTE:one\u{2028}two\r\nEND:VCARD\r\n"
+        XCTAssertEqual(VCard.refold(card, width: 0), card)
+    }
+}
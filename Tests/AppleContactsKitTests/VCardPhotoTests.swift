@@ -0,0 +1,40 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class VCardPhotoTests: XCTestCase {
+    private let card = "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Erik Fisher\r\nEND:VCARD\r\n"
+
+    func testPhotoIsInsertedBeforeEnd() {
+        let data = Data(repeating: 0xAB, count: 200)
+        let result = VCard.injectPhoto(card, data: data, type: "jpeg")
+
+        let lines = VCard.lines(result)
+        XCTAssertEqual(lines.last, "END:VCARD")
+        XCTAssertEqual(lines[lines.count - 2], "PHOTO;ENCODING=b;TYPE=JPEG:\(data.base64EncodedString())")
+        XCTAssertTrue(result.hasPrefix("BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Erik Fisher\r\nPHOTO;ENCODING=b;TYPE=JPEG:"))
+    }
+
+    func testPhotoIsFolded() {
+        let result = VCard.injectPhoto(card, data: Data(repeating: 0xAB, count: 200), type: "PNG")
+        let physical = result.components(separatedBy: "\r\n").filter { !$0.isEmpty }
+
+        XCTAssertGreaterThan(physical.count, 5)
+        XCTAssertTrue(physical.allSatisfy { $0.utf8.count <= 75 })
+        let photo = physical.drop { !$0.hasPrefix("PHOTO") }.dropFirst().prefix { $0 != "END:VCARD" }
+        XCTAssertFalse(photo.isEmpty)
+        XCTAssertTrue(photo.allSatisfy { $0.hasPrefix(" ") })
+    }
+
+    func testLFCardsStayLF() {
+        let result = VCard.injectPhoto(card.replacingOccurrences(of: "\r\n", with: "\n"), data: Data([1, 2, 3]), type: "GIF")
+        XCTAssertFalse(result.contains("\r"))
+        XCTAssertTrue(result.hasSuffix("PHOTO;ENCODING=b;TYPE=GIF:AQID\nEND:VCARD\n"))
+    }
+
+    func testImageTypeFromMagicBytes() {
+        XCTAssertEqual(VCard.imageType(of: Data([0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A])), "PNG")
+        XCTAssertEqual(VCard.imageType(of: Data("GIF89a".utf8)), "GIF")
+        XCTAssertEqual(VCard.imageType(of: Data([0xFF, 0xD8, 0xFF, 0xE0])), "JPEG")
+        XCTAssertEqual(VCard.imageType(of: Data()), "JPEG")
+    }
+}
@@ -0,0 +1,29 @@
+import XCTest
+@testable import AppleContactsKit
+
+final class WriteOptionsTests: XCTestCase {
+    func testCreateTakesWriteOptions() throws {
+        let create = try Create.parse(["--first-name", "Erik", "--phone-region", "no", "--id-format", "uuid", "-q", "--verbose", "--log-json"])
+        XCTAssertEqual(create.options.resolvedPhoneRegion, "NO")
+        XCTAssertEqual(create.options.displayID("ABC-123:ABPerson"), "ABC-123")
+        XCTAssertTrue(create.options.quiet)
+        XCTAssertTrue(create.options.logOptions.verbose)
+        XCTAssertTrue(create.options.logOptions.logJson)
+    }
+
+    func testImportTakesWriteOptions() throws {
+        let importCommand = try Import.parse(["contacts.vcf", "--phone-region", "SE", "--quiet"])
+        XCTAssertEqual(importCommand.options.resolvedPhoneRegion, "SE")
+        XCTAssertTrue(importCommand.options.quiet)
+    }
+
+    func testDisplayOptionsAreRejected() {
+        XCTAssertThrowsError(try Create.parse(["--first-name", "Erik", "--locale", "nb-NO"]))
+        XCTAssertThrowsError(try Import.parse(["contacts.vcf", "--redact", "phones"]))
+        XCTAssertThrowsError(try Import.parse(["contacts.vcf", "--jsonpath", "$.name"]))
+    }
+
+    func testUnknownPhoneRegionIsRejected() {
+        XCTAssertThrowsError(try Create.parse(["--first-name", "Erik", "--phone-region", "XX"]))
+    }
+}